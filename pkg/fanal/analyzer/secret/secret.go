@@ -6,16 +6,13 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/samber/lo"
-	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
 	"github.com/aquasecurity/trivy/pkg/fanal/secret"
 	"github.com/aquasecurity/trivy/pkg/fanal/types"
-	"github.com/aquasecurity/trivy/pkg/fanal/utils"
 )
 
 // To make sure SecretAnalyzer implements analyzer.Initializer
@@ -23,23 +20,6 @@ var _ analyzer.Initializer = &SecretAnalyzer{}
 
 const version = 1
 
-var (
-	skipFiles = []string{
-		"go.mod",
-		"go.sum",
-		"package-lock.json",
-		"yarn.lock",
-		"pnpm-lock.yaml",
-		"Pipfile.lock",
-		"Gemfile.lock",
-	}
-	skipDirs = []string{".git", "node_modules"}
-	skipExts = []string{
-		".jpg", ".png", ".gif", ".doc", ".pdf", ".bin", ".svg", ".socket", ".deb", ".rpm",
-		".zip", ".gz", ".gzip", ".tar", ".pyc",
-	}
-)
-
 func init() {
 	// The scanner will be initialized later via InitScanner()
 	analyzer.RegisterAnalyzer(NewSecretAnalyzer(secret.Scanner{}, ""))
@@ -75,10 +55,10 @@ func (a *SecretAnalyzer) Init(opt analyzer.AnalyzerOptions) error {
 	return nil
 }
 
-func (a *SecretAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+func (a *SecretAnalyzer) Analyze(ctx context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
 	// Do not scan binaries
-	binary, err := utils.IsBinary(input.Content, input.Info.Size())
-	if binary || err != nil {
+	binary, err := a.scanner.IsBinary(input.Content, input.Info.Size())
+	if err != nil || binary && !a.scanner.ScanCompressed {
 		return nil, nil
 	}
 
@@ -95,12 +75,22 @@ func (a *SecretAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput
 		filePath = fmt.Sprintf("/%s", filePath)
 	}
 
-	result := a.scanner.Scan(secret.ScanArgs{
+	// Compressed files are decompressed by the scanner
+	if binary && !a.scanner.Compressed(filePath, content) {
+		return nil, nil
+	}
+
+	args := secret.ScanArgs{
 		FilePath: filePath,
 		Content:  content,
-	})
+	}
+	// Permissions are meaningful only for files on the local filesystem
+	if input.Dir != "" {
+		args.Info = input.Info
+	}
+	result := a.scanner.ScanContext(ctx, args)
 
-	if len(result.Findings) == 0 {
+	if len(result.Findings) == 0 && !result.Clean {
 		return nil, nil
 	}
 
@@ -110,43 +100,11 @@ func (a *SecretAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput
 }
 
 func (a *SecretAnalyzer) Required(filePath string, fi os.FileInfo) bool {
-	// Skip small files
-	if fi.Size() < 10 {
-		return false
-	}
-
-	dir, fileName := filepath.Split(filePath)
-	dir = filepath.ToSlash(dir)
-	dirs := strings.Split(dir, "/")
-
-	// Check if the directory should be skipped
-	for _, skipDir := range skipDirs {
-		if slices.Contains(dirs, skipDir) {
-			return false
-		}
-	}
-
-	// Check if the file should be skipped
-	if slices.Contains(skipFiles, fileName) {
-		return false
-	}
-
 	// Skip the config file for secret scanning
 	if filepath.Base(a.configPath) == filePath {
 		return false
 	}
-
-	// Check if the file extension should be skipped
-	ext := filepath.Ext(fileName)
-	if slices.Contains(skipExts, ext) {
-		return false
-	}
-
-	if a.scanner.AllowPath(filePath) {
-		return false
-	}
-
-	return true
+	return a.scanner.Required(filePath, fi)
 }
 
 func (a *SecretAnalyzer) Type() analyzer.Type {
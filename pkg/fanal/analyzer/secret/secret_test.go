@@ -95,6 +95,37 @@ func TestSecretAnalyzer(t *testing.T) {
 			},
 		},
 	}
+	wantFindingHiddenFile := types.SecretFinding{
+		RuleID:    "aws-access-key-id",
+		Category:  "AWS",
+		Title:     "AWS Access Key ID",
+		Severity:  "CRITICAL",
+		StartLine: 2,
+		EndLine:   2,
+		Match:     "aws_access_key_id = ********************",
+		Code: types.Code{
+			Lines: []types.Line{
+				{
+					Number:      1,
+					Content:     "[default]",
+					Highlighted: "[default]",
+				},
+				{
+					Number:      2,
+					Content:     "aws_access_key_id = ********************",
+					IsCause:     true,
+					Highlighted: "aws_access_key_id = ********************",
+					FirstCause:  true,
+					LastCause:   true,
+				},
+				{
+					Number:      3,
+					Content:     "",
+					Highlighted: "",
+				},
+			},
+		},
+	}
 	tests := []struct {
 		name       string
 		configPath string
@@ -116,6 +147,20 @@ func TestSecretAnalyzer(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:       "scan hidden file",
+			configPath: "",
+			filePath:   "testdata/.aws/credentials",
+			dir:        ".",
+			want: &analyzer.AnalysisResult{
+				Secrets: []types.Secret{
+					{
+						FilePath: "testdata/.aws/credentials",
+						Findings: []types.SecretFinding{wantFindingHiddenFile},
+					},
+				},
+			},
+		},
 		{
 			name:       "image scan return result",
 			configPath: "testdata/image-config.yaml",
@@ -176,15 +221,21 @@ func TestSecretAnalyzer(t *testing.T) {
 
 func TestSecretRequire(t *testing.T) {
 	tests := []struct {
-		name     string
-		filePath string
-		want     bool
+		name       string
+		configPath string
+		filePath   string
+		want       bool
 	}{
 		{
 			name:     "pass regular file",
 			filePath: "testdata/secret.txt",
 			want:     true,
 		},
+		{
+			name:     "pass hidden file",
+			filePath: "testdata/.aws/credentials",
+			want:     true,
+		},
 		{
 			name:     "skip small file",
 			filePath: "testdata/emptyfile",
@@ -205,12 +256,32 @@ func TestSecretRequire(t *testing.T) {
 			filePath: "testdata/secret.doc",
 			want:     false,
 		},
+		{
+			name:       "pass listed extension",
+			configPath: "testdata/scan-exts.yaml",
+			filePath:   "testdata/app.env",
+			want:       true,
+		},
+		{
+			name:       "skip unlisted extension",
+			configPath: "testdata/scan-exts.yaml",
+			filePath:   "testdata/main.go",
+			want:       false,
+		},
+		{
+			name:       "skip folder with listed extension",
+			configPath: "testdata/scan-exts.yaml",
+			filePath:   "testdata/node_modules/app.env",
+			want:       false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			a := secret.SecretAnalyzer{}
-			err := a.Init(analyzer.AnalyzerOptions{})
+			err := a.Init(analyzer.AnalyzerOptions{
+				SecretScannerOption: analyzer.SecretScannerOption{ConfigPath: tt.configPath},
+			})
 			require.NoError(t, err)
 
 			fi, err := os.Stat(tt.filePath)
@@ -0,0 +1,73 @@
+package secret
+
+import (
+	"sync"
+
+	"github.com/samber/lo"
+)
+
+// allowUsage records which allow rules have suppressed secrets or paths
+type allowUsage struct {
+	mu     sync.Mutex
+	custom []*AllowRule
+	used   map[*AllowRule]struct{}
+}
+
+func newAllowUsage(custom []*AllowRule) *allowUsage {
+	return &allowUsage{
+		custom: custom,
+		used:   map[*AllowRule]struct{}{},
+	}
+}
+
+// customAllowRules returns allow rules defined in the config, i.e. global allow rules and
+// allow rules of rules which are not built-in.
+func customAllowRules(rules []Rule, allowRules AllowRules) []*AllowRule {
+	var custom []*AllowRule
+	for i := range allowRules {
+		if !lo.ContainsBy(builtinAllowRules, func(r AllowRule) bool { return r.ID == allowRules[i].ID }) {
+			custom = append(custom, &allowRules[i])
+		}
+	}
+	for _, rule := range rules {
+		if lo.ContainsBy(builtinRules, func(r Rule) bool { return r.ID == rule.ID }) {
+			continue
+		}
+		for i := range rule.AllowRules {
+			custom = append(custom, &rule.AllowRules[i])
+		}
+	}
+	return custom
+}
+
+// used records the usage of the allow rule and returns true if the rule is not nil
+func (g Global) used(rule *AllowRule) bool {
+	if rule == nil {
+		return false
+	}
+	if g.allowUsage != nil {
+		g.allowUsage.mu.Lock()
+		g.allowUsage.used[rule] = struct{}{}
+		g.allowUsage.mu.Unlock()
+	}
+	return true
+}
+
+// UnusedAllowRules returns custom allow rules which have not suppressed anything since the scanner was created.
+// It helps to prune allow rules which no longer match anything.
+// Built-in allow rules are not reported.
+func (s *Scanner) UnusedAllowRules() []AllowRule {
+	if s.allowUsage == nil {
+		return nil
+	}
+	s.allowUsage.mu.Lock()
+	defer s.allowUsage.mu.Unlock()
+
+	unused := lo.Filter(s.allowUsage.custom, func(r *AllowRule, _ int) bool {
+		_, ok := s.allowUsage.used[r]
+		return !ok
+	})
+	return lo.Map(unused, func(r *AllowRule, _ int) AllowRule {
+		return *r
+	})
+}
@@ -0,0 +1,66 @@
+package secret_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/secret"
+)
+
+func TestScanner_UnusedAllowRules(t *testing.T) {
+	tests := []struct {
+		name           string
+		configPath     string
+		inputFilePaths []string
+		want           []string
+	}{
+		{
+			name:           "one allow rule matched",
+			configPath:     "testdata/unused-allow-rules.yaml",
+			inputFilePaths: []string{"testdata/secret.txt"},
+			want: []string{
+				"unused-value",
+				"skip-shell",
+			},
+		},
+		{
+			name:       "no scan",
+			configPath: "testdata/unused-allow-rules.yaml",
+			want: []string{
+				"some-value",
+				"unused-value",
+				"skip-shell",
+			},
+		},
+		{
+			name:           "built-in allow rules are not reported",
+			inputFilePaths: []string{"testdata/secret.txt"},
+			want:           []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := secret.ParseConfig(tt.configPath)
+			require.NoError(t, err)
+
+			s := secret.NewScanner(c)
+			for _, filePath := range tt.inputFilePaths {
+				content, err := os.ReadFile(filePath)
+				require.NoError(t, err)
+				s.Scan(secret.ScanArgs{
+					FilePath: filePath,
+					Content:  content,
+				})
+			}
+
+			got := lo.Map(s.UnusedAllowRules(), func(r secret.AllowRule, _ int) string {
+				return r.ID
+			})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
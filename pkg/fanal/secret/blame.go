@@ -0,0 +1,79 @@
+package secret
+
+import (
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/log"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// blamer attributes secrets to the authors of their lines by git blame at HEAD
+type blamer struct {
+	repo    *git.Repository
+	head    *object.Commit
+	commits map[plumbing.Hash]*object.Commit
+}
+
+// newBlamer returns nil if the repository has no commits
+func newBlamer(repo *git.Repository) *blamer {
+	ref, err := repo.Head()
+	if err != nil {
+		log.Logger.Debugf("Unable to resolve HEAD for git blame: %s", err)
+		return nil
+	}
+	head, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		log.Logger.Debugf("Unable to get the HEAD commit for git blame: %s", err)
+		return nil
+	}
+	return &blamer{
+		repo:    repo,
+		head:    head,
+		commits: make(map[plumbing.Hash]*object.Commit),
+	}
+}
+
+// attribute populates the authors of the findings. Findings on lines which are not committed are left as is.
+func (b *blamer) attribute(result *types.Secret, content []byte) {
+	if b == nil || len(result.Findings) == 0 {
+		return
+	}
+
+	blame, err := git.Blame(b.head, result.FilePath)
+	if err != nil {
+		// e.g. untracked files
+		log.Logger.Debugf("Unable to blame %s: %s", result.FilePath, err)
+		return
+	}
+
+	lines := strings.Split(string(content), "\n")
+	for i := range result.Findings {
+		finding := &result.Findings[i]
+		n := finding.StartLine - 1
+		if n < 0 || n >= len(blame.Lines) || n >= len(lines) || blame.Lines[n].Text != lines[n] {
+			continue
+		}
+
+		line := blame.Lines[n]
+		finding.AuthorEmail = line.Author
+		if commit := b.commit(line.Hash); commit != nil {
+			finding.Author = commit.Author.Name
+		}
+	}
+}
+
+func (b *blamer) commit(hash plumbing.Hash) *object.Commit {
+	if commit, ok := b.commits[hash]; ok {
+		return commit
+	}
+	commit, err := b.repo.CommitObject(hash)
+	if err != nil {
+		log.Logger.Debugf("Unable to get the commit %s for git blame: %s", hash, err)
+	}
+	b.commits[hash] = commit
+	return commit
+}
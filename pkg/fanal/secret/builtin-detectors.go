@@ -0,0 +1,163 @@
+package secret
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+var builtinDetectors = []Detector{
+	htpasswdDetector{},
+	kubeconfigDetector{},
+}
+
+// 13-19 digits optionally separated by spaces or dashes
+var creditCardRegex = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+
+// creditCardDetector detects payment card numbers (PAN).
+// Only numbers with a known issuer prefix that pass the Luhn check are reported.
+// It is enabled by ScanCreditCards as order IDs and other numbers may pass the check as well.
+type creditCardDetector struct{}
+
+func (creditCardDetector) Name() string {
+	return "credit-card"
+}
+
+func (creditCardDetector) Detect(content []byte, _ string) []Finding {
+	if !creditCardRegex.Match(content) {
+		return nil
+	}
+
+	var findings []Finding
+	for _, loc := range creditCardRegex.FindAllIndex(content, -1) {
+		digits := bytes.Map(func(r rune) rune {
+			if r == ' ' || r == '-' {
+				return -1
+			}
+			return r
+		}, content[loc[0]:loc[1]])
+
+		issuer := cardIssuer(digits)
+		if issuer == "" || !luhn(digits) {
+			continue
+		}
+
+		findings = append(findings, Finding{
+			Category: CategoryCreditCard,
+			Title:    issuer + " Credit Card Number",
+			Severity: "HIGH",
+			Location: Location{
+				Start: loc[0],
+				End:   loc[1],
+			},
+		})
+	}
+	return findings
+}
+
+// cardIssuer returns the card issuer based on the prefix and the length of the number.
+// It returns an empty string if the issuer is unknown.
+func cardIssuer(digits []byte) string {
+	prefix := func(n int) int {
+		i, _ := strconv.Atoi(string(digits[:n]))
+		return i
+	}
+
+	switch l := len(digits); {
+	case digits[0] == '4' && (l == 13 || l == 16 || l == 19):
+		return "Visa"
+	case l == 16 && (prefix(2) >= 51 && prefix(2) <= 55 || prefix(4) >= 2221 && prefix(4) <= 2720):
+		return "Mastercard"
+	case l == 15 && (prefix(2) == 34 || prefix(2) == 37):
+		return "American Express"
+	}
+	return ""
+}
+
+// luhn validates the number using the Luhn algorithm
+func luhn(digits []byte) bool {
+	var sum int
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-i)%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+var (
+	htpasswdFiles = []string{".htpasswd", "htpasswd", ".htdigest", "htdigest"}
+
+	// e.g. "user:$apr1$..." and "user:realm:hash" for htdigest
+	htpasswdRegex = regexp.MustCompile(`(?m)^[^:#\s]+:(?:[^:\s]+:)?(?P<hash>[^:\s]+)\s*$`)
+)
+
+// htpasswdDetector detects password hashes in .htpasswd and similar files
+type htpasswdDetector struct{}
+
+func (htpasswdDetector) Name() string {
+	return "htpasswd"
+}
+
+func (htpasswdDetector) Detect(content []byte, path string) []Finding {
+	if !slices.Contains(htpasswdFiles, filepath.Base(path)) {
+		return nil
+	}
+
+	var findings []Finding
+	hashIndex := htpasswdRegex.SubexpIndex("hash")
+	for _, loc := range htpasswdRegex.FindAllSubmatchIndex(content, -1) {
+		start, end := loc[2*hashIndex], loc[2*hashIndex+1]
+		hashType, severity := htpasswdHashType(string(content[start:end]))
+		findings = append(findings, Finding{
+			Category: CategoryHtpasswd,
+			Title:    "htpasswd " + hashType,
+			Severity: severity,
+			Location: Location{
+				Start: start,
+				End:   end,
+			},
+		})
+	}
+	return findings
+}
+
+// htpasswdHashType classifies the password hash.
+// Weak hashes and plain text passwords have higher severity as they are easier to crack.
+func htpasswdHashType(hash string) (string, string) {
+	switch {
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		return "bcrypt password hash", "MEDIUM"
+	case strings.HasPrefix(hash, "$5$"):
+		return "SHA-256 crypt password hash", "MEDIUM"
+	case strings.HasPrefix(hash, "$6$"):
+		return "SHA-512 crypt password hash", "MEDIUM"
+	case strings.HasPrefix(hash, "$apr1$"):
+		return "APR1-MD5 password hash", "HIGH"
+	case strings.HasPrefix(hash, "{SHA}"):
+		return "SHA-1 password hash", "HIGH"
+	case len(hash) == 32 && isHex(hash):
+		return "MD5 digest password hash", "HIGH"
+	case len(hash) == 13:
+		return "DES crypt password hash", "HIGH"
+	}
+	return "plain text password", "CRITICAL"
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package secret
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/bmatcuk/doublestar"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/log"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// configHash returns the hash of the config so that clean results can be tied to the config they were scanned with.
+// Programmatic hooks are not included.
+func configHash(config *Config) string {
+	b, err := yaml.Marshal(config)
+	if err != nil {
+		log.Logger.Debugf("Unable to marshal the secret config: %s", err)
+		return ""
+	}
+	h := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(h[:])
+}
+
+// clean returns the result with no findings. It is marked as clean if the file matches EmitCleanFiles.
+func (g Global) clean(filePath string) types.Secret {
+	for _, glob := range g.EmitCleanFiles {
+		if matched, err := doublestar.Match(glob, strings.TrimPrefix(filePath, "/")); err == nil && matched {
+			return types.Secret{
+				FilePath:   filePath,
+				Clean:      true,
+				ConfigHash: g.configHash,
+			}
+		}
+	}
+	return types.Secret{}
+}
@@ -0,0 +1,79 @@
+package secret
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var (
+	// e.g. "curl -u admin:secret https://..." including lines continued by a backslash
+	commandLineRegex = regexp.MustCompile(`(?m)(?:^|[\s;|&(` + "`" + `])(?P<cmd>curl|wget)\s(?:[^\n]*\\\r?\n)*[^\n]*`)
+
+	// e.g. -u user:pass and --user=user:pass for curl
+	curlUserRegex = regexp.MustCompile(`(?:^|\s)(?P<flag>-u|--user|--proxy-user|-U)(?:\s+|=)["']?[^\s:"']*:(?P<secret>[^\s"']+)`)
+
+	// e.g. --password=pass and --http-password pass for wget
+	wgetPasswordRegex = regexp.MustCompile(`(?:^|\s)(?P<flag>--(?:http-|proxy-|ftp-)?password)(?:\s+|=)["']?(?P<secret>[^\s"']+)`)
+
+	// e.g. -H "Authorization: Bearer ..." for curl and --header="X-Api-Key: ..." for wget
+	headerRegex = regexp.MustCompile(`(?i)(?:^|\s)(?P<flag>-H|--header)(?:\s+|=)["'](?P<header>(?:Proxy-)?Authorization|X-[\w-]*(?:Key|Token)|Private-Token|Api-?Key)\s*:\s*(?:(?:Bearer|Basic|Token|Digest)\s+)?(?P<secret>[^\s"']+)`)
+)
+
+// commandLineDetector detects credentials passed to curl and wget in command lines,
+// i.e. basic auth credentials and authorization headers. It is enabled by ScanCommandLines.
+type commandLineDetector struct{}
+
+func (commandLineDetector) Name() string {
+	return "command-line-credential"
+}
+
+func (commandLineDetector) Detect(content []byte, _ string) []Finding {
+	var findings []Finding
+	cmdIndex := commandLineRegex.SubexpIndex("cmd")
+	for _, loc := range commandLineRegex.FindAllSubmatchIndex(content, -1) {
+		cmd := string(content[loc[2*cmdIndex]:loc[2*cmdIndex+1]])
+		start, end := loc[2*cmdIndex+1], loc[1]
+
+		flagRegexes := []*regexp.Regexp{headerRegex, curlUserRegex}
+		if cmd == "wget" {
+			flagRegexes = []*regexp.Regexp{headerRegex, wgetPasswordRegex}
+		}
+		for _, r := range flagRegexes {
+			findings = append(findings, commandLineFlags(r, cmd, content[start:end], start)...)
+		}
+	}
+	return findings
+}
+
+// commandLineFlags returns the secrets passed by the flags matching the regex.
+// Locations are offset by the start of the command line in the content.
+func commandLineFlags(r *regexp.Regexp, cmd string, line []byte, offset int) []Finding {
+	var findings []Finding
+	flagIndex, secretIndex, headerIndex := r.SubexpIndex("flag"), r.SubexpIndex("secret"), r.SubexpIndex("header")
+	for _, loc := range r.FindAllSubmatchIndex(line, -1) {
+		start, end := loc[2*secretIndex], loc[2*secretIndex+1]
+		// Skip variable references, e.g. "$TOKEN" and "${PASSWORD}"
+		if line[start] == '$' {
+			continue
+		}
+
+		context := fmt.Sprintf("%s %s", cmd, line[loc[2*flagIndex]:loc[2*flagIndex+1]])
+		title := fmt.Sprintf("Credential in %s command line", cmd)
+		if headerIndex != -1 {
+			header := string(line[loc[2*headerIndex]:loc[2*headerIndex+1]])
+			context += " " + header
+			title = fmt.Sprintf("%s header in %s command line", header, cmd)
+		}
+		findings = append(findings, Finding{
+			Category: CategoryGeneric,
+			Title:    title,
+			Severity: "HIGH",
+			Location: Location{
+				Start: offset + start,
+				End:   offset + end,
+			},
+			Context: context,
+		})
+	}
+	return findings
+}
@@ -0,0 +1,83 @@
+package secret_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/secret"
+)
+
+func TestScanner_ScanCommandLines(t *testing.T) {
+	type finding struct {
+		Line    int
+		Title   string
+		Match   string
+		Context string
+	}
+	tests := []struct {
+		name             string
+		scanCommandLines bool
+		want             []finding
+	}{
+		{
+			name:             "scan command lines",
+			scanCommandLines: true,
+			want: []finding{
+				{
+					Line:    3,
+					Title:   "Authorization header in curl command line",
+					Match:   `curl -s -H "Authorization: Bearer ********************************" \`,
+					Context: "curl -H Authorization",
+				},
+				{
+					Line:    2,
+					Title:   "Credential in curl command line",
+					Match:   "curl -u admin:************** https://api.example.com/login",
+					Context: "curl -u",
+				},
+				{
+					Line:    6,
+					Title:   "X-Api-Key header in wget command line",
+					Match:   `wget --header="X-Api-Key: ********************************" https://api.example.com/export`,
+					Context: "wget --header X-Api-Key",
+				},
+				{
+					Line:    7,
+					Title:   "Credential in wget command line",
+					Match:   "wget --user=admin --password=************** https://ftp.example.com/dump.sql",
+					Context: "wget --password",
+				},
+			},
+		},
+		{
+			name: "disabled",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := os.ReadFile("testdata/curl.sh")
+			require.NoError(t, err)
+
+			s := secret.NewScanner(&secret.Config{ScanCommandLines: tt.scanCommandLines})
+			got := s.Scan(secret.ScanArgs{
+				FilePath: "testdata/curl.sh",
+				Content:  content,
+			})
+
+			var findings []finding
+			for _, f := range got.Findings {
+				assert.Equal(t, "command-line-credential", f.RuleID)
+				findings = append(findings, finding{
+					Line:    f.StartLine,
+					Title:   f.Title,
+					Match:   f.Match,
+					Context: f.Context,
+				})
+			}
+			assert.Equal(t, tt.want, findings)
+		})
+	}
+}
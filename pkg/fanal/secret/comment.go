@@ -0,0 +1,112 @@
+package secret
+
+import (
+	"bytes"
+	"sync"
+
+	"golang.org/x/exp/slices"
+)
+
+var (
+	defaultCommentPrefixes = []string{"//", "#"}
+	blockCommentStart      = []byte("/*")
+	blockCommentEnd        = []byte("*/")
+
+	// Languages with `/* */` comments. "/*" in other files such as "rm -rf /*" in shell scripts doesn't start a comment.
+	blockCommentLanguages = []string{
+		"go", "javascript", "typescript", "java", "kotlin", "scala", "csharp", "php", "rust", "swift", "c", "cpp", "sql", "hcl",
+	}
+)
+
+// inComment reports whether the location lies within a comment.
+// It is a heuristic supporting line comments starting with CommentPrefixes in any file and `/* */` comments
+// in C-like languages. Block comments count only if they are closed after the location.
+func (g Global) inComment(filePath string, content []byte, loc Location) bool {
+	lineStart := bytes.LastIndex(content[:loc.Start], lineSep) + 1
+	before := content[lineStart:loc.Start]
+	for _, prefix := range g.CommentPrefixes {
+		if prefix != "" && hasLineComment(before, []byte(prefix)) {
+			return true
+		}
+	}
+
+	if !slices.Contains(blockCommentLanguages, language(filePath)) {
+		return false
+	}
+
+	// Check if the location is after "/*" that is not closed yet, and "*/" follows
+	open := lastBlockCommentStart(content[:loc.Start])
+	if open == -1 || bytes.Contains(content[open+len(blockCommentStart):loc.Start], blockCommentEnd) {
+		return false
+	}
+	return bytes.Contains(content[loc.End:], blockCommentEnd)
+}
+
+// lastBlockCommentStart returns the index of the last "/*" at the beginning of the content or after a whitespace,
+// or -1 if there is none. "/*" in globs and paths such as "src/**/*.go" and "/tmp/*" doesn't start a comment.
+func lastBlockCommentStart(content []byte) int {
+	for end := len(content); ; {
+		i := bytes.LastIndex(content[:end], blockCommentStart)
+		if i <= 0 || isSpace(content[i-1]) {
+			return i
+		}
+		end = i
+	}
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// hasLineComment checks if the comment prefix appears at the beginning of the line or after a whitespace
+// so that "//" in URLs such as "https://" is not treated as a comment.
+func hasLineComment(line, prefix []byte) bool {
+	for i := range line {
+		if bytes.HasPrefix(line[i:], prefix) && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			return true
+		}
+	}
+	return false
+}
+
+// CommentStats counts findings located in comments and in code, e.g. to decide whether to adopt CommentSeverityDelta
+type CommentStats struct {
+	InComments int
+	InCode     int
+}
+
+type commentStats struct {
+	mu    sync.Mutex
+	stats CommentStats
+}
+
+func newCommentStats(enabled bool) *commentStats {
+	if !enabled {
+		return nil
+	}
+	return &commentStats{}
+}
+
+func (c *commentStats) record(inComment bool) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if inComment {
+		c.stats.InComments++
+	} else {
+		c.stats.InCode++
+	}
+}
+
+// CommentStats returns how many findings have been found in comments and in code since the scanner was created.
+// Comments are detected in the same way as CommentSeverityDelta. It is zero unless ReportCommentStats is enabled.
+func (s *Scanner) CommentStats() CommentStats {
+	if s.commentStats == nil {
+		return CommentStats{}
+	}
+	s.commentStats.mu.Lock()
+	defer s.commentStats.mu.Unlock()
+	return s.commentStats.stats
+}
@@ -0,0 +1,83 @@
+package secret
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"path/filepath"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+var (
+	CategoryDiagnostic = types.SecretRuleCategory("Diagnostic")
+
+	gzipMagic = []byte{0x1f, 0x8b}
+	gzipExts  = []string{".gz", ".gzip"}
+)
+
+// maxDecompressedSize limits the size of decompressed content to protect against decompression bombs
+const maxDecompressedSize = 100 << 20
+
+// binaryHeadSize is the size of the head which is inspected to determine if the content is binary
+const binaryHeadSize = 300
+
+// Compressed checks if the file will be decompressed before scanning.
+// Such files should be scanned even if they look binary.
+func (g Global) Compressed(filePath string, content []byte) bool {
+	return g.ScanCompressed && isGzip(filePath, content)
+}
+
+// isGzip checks if the file claims to be compressed by gzip, by the extension or the magic number
+func isGzip(filePath string, content []byte) bool {
+	return slices.Contains(gzipExts, filepath.Ext(filePath)) || bytes.HasPrefix(content, gzipMagic)
+}
+
+// decompressedBinary checks if the decompressed content of the gzip file is binary. Only the head is decompressed.
+// Malformed files are not regarded as binary so that Scan reports the failure.
+func (g Global) decompressedBinary(content []byte) bool {
+	r, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return false
+	}
+	defer r.Close()
+
+	head, err := io.ReadAll(io.LimitReader(r, binaryHeadSize))
+	if err != nil {
+		return false
+	}
+	binary, err := g.IsBinary(bytes.NewReader(head), int64(len(head)))
+	return binary && err == nil
+}
+
+func gunzip(content []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, xerrors.Errorf("gzip reader error: %w", err)
+	}
+	defer r.Close()
+
+	b, err := io.ReadAll(io.LimitReader(r, maxDecompressedSize+1))
+	if err != nil {
+		return nil, xerrors.Errorf("gzip read error: %w", err)
+	} else if len(b) > maxDecompressedSize {
+		return nil, xerrors.Errorf("decompressed size exceeds %d bytes", maxDecompressedSize)
+	}
+	return b, nil
+}
+
+// decodeFailure returns a diagnostic finding so that users know the file was not fully scanned
+func decodeFailure(encoding string, err error) types.SecretFinding {
+	return types.SecretFinding{
+		RuleID:    "decode-failure",
+		Category:  CategoryDiagnostic,
+		Severity:  "LOW",
+		Title:     "Failed to decode " + encoding + " content, the file was not scanned for secrets",
+		StartLine: 1,
+		EndLine:   1,
+		Match:     err.Error(),
+	}
+}
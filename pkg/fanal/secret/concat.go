@@ -0,0 +1,33 @@
+package secret
+
+import (
+	"regexp"
+)
+
+// e.g. "abc" + "def" and 'abc' + 'def'
+var concatenationRegex = regexp.MustCompile(`"[ \t]*\+[ \t]*"|'[ \t]*\+[ \t]*'`)
+
+// collapseConcatenations joins adjacent string literals concatenated with "+" on the same line,
+// e.g. `"abc" + "def"` becomes `"abcdef"`, so that secrets split into several literals can be detected.
+func collapseConcatenations(content []byte) *normalizer {
+	locs := concatenationRegex.FindAllIndex(content, -1)
+	if len(locs) == 0 {
+		return &normalizer{content: content}
+	}
+
+	n := &normalizer{
+		content: make([]byte, 0, len(content)),
+		starts:  make([]int, 0, len(content)),
+		ends:    make([]int, 0, len(content)),
+	}
+	var prev int
+	for _, loc := range append(locs, []int{len(content), len(content)}) {
+		for i := prev; i < loc[0]; i++ {
+			n.starts = append(n.starts, i)
+			n.ends = append(n.ends, i+1)
+		}
+		n.content = append(n.content, content[prev:loc[0]]...)
+		prev = loc[1]
+	}
+	return n
+}
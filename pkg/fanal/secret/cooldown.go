@@ -0,0 +1,41 @@
+package secret
+
+import "bytes"
+
+// cooldown suppresses matches of the same secret within the lines after the last reported one.
+// Matches must be given in the order of their locations.
+type cooldown struct {
+	content  []byte
+	lines    int
+	offset   int
+	line     int
+	reported map[string]int
+}
+
+// newCooldown returns a cooldown of the lines. It returns nil, which suppresses nothing, if lines is not positive.
+func newCooldown(content []byte, lines int) *cooldown {
+	if lines <= 0 {
+		return nil
+	}
+	return &cooldown{
+		content:  content,
+		lines:    lines,
+		reported: make(map[string]int),
+	}
+}
+
+// suppress checks if the secret at the location is suppressed, otherwise records it as reported
+func (c *cooldown) suppress(loc Location) bool {
+	if c == nil {
+		return false
+	}
+	c.line += bytes.Count(c.content[c.offset:loc.Start], lineSep)
+	c.offset = loc.Start
+
+	secret := string(c.content[loc.Start:loc.End])
+	if last, ok := c.reported[secret]; ok && c.line-last <= c.lines {
+		return true
+	}
+	c.reported[secret] = c.line
+	return false
+}
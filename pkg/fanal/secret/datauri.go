@@ -0,0 +1,64 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+)
+
+// e.g. data:application/json;base64,eyJ0b2tlbiI6Ii4uLiJ9 and data:;charset=utf-8;base64,...
+var dataURIRegex = regexp.MustCompile(`data:(?P<type>[\w.+\-]+/[\w.+\-]+)?(?:;[\w.+\-]+=[\w.+\-%]+)*;base64,(?P<payload>[A-Za-z0-9+/]+={0,2})`)
+
+// maxDataURISize limits the size of decoded data URI payloads
+const maxDataURISize = 1 << 20
+
+// findDataURIMatches returns secrets in the decoded payloads of data URIs.
+// The secrets are located at the whole payloads as they can't be located in the encoded content.
+func (s *Scanner) findDataURIMatches(args ScanArgs, pathAllowed bool) []Match {
+	if !s.ScanDataURIs || !bytes.Contains(args.Content, []byte("data:")) {
+		return nil
+	}
+
+	var matches []Match
+	typeIndex, payloadIndex := dataURIRegex.SubexpIndex("type"), dataURIRegex.SubexpIndex("payload")
+	for _, loc := range dataURIRegex.FindAllSubmatchIndex(args.Content, -1) {
+		payload := Location{Start: loc[2*payloadIndex], End: loc[2*payloadIndex+1]}
+		decoded, ok := decodeDataURI(args.Content[payload.Start:payload.End])
+		if !ok {
+			continue
+		} else if binary, err := s.IsBinary(bytes.NewReader(decoded), int64(len(decoded))); binary || err != nil {
+			continue
+		}
+
+		mediaType := "text/plain"
+		if loc[2*typeIndex] != -1 {
+			mediaType = string(args.Content[loc[2*typeIndex]:loc[2*typeIndex+1]])
+		}
+		for _, m := range s.findMatches(ScanArgs{FilePath: args.FilePath, Content: decoded}, pathAllowed) {
+			matches = append(matches, Match{
+				Rule:     m.Rule,
+				Location: payload,
+				Region:   payload,
+				Context:  fmt.Sprintf("data URI (%s)", mediaType),
+				decoded:  m.secret(decoded),
+			})
+		}
+	}
+	return matches
+}
+
+// decodeDataURI decodes the base64 payload. Payloads which are too large are not decoded.
+func decodeDataURI(payload []byte) ([]byte, bool) {
+	if base64.StdEncoding.DecodedLen(len(payload)) > maxDataURISize {
+		return nil, false
+	}
+	decoded := make([]byte, base64.StdEncoding.DecodedLen(len(payload)))
+	n, err := base64.StdEncoding.Decode(decoded, payload)
+	if err != nil {
+		if n, err = base64.RawStdEncoding.Decode(decoded, payload); err != nil {
+			return nil, false
+		}
+	}
+	return decoded[:n], true
+}
@@ -0,0 +1,74 @@
+package secret
+
+import "sort"
+
+// deduplicateMatches keeps only one of the matches of different rules whose secrets overlap.
+// The winner is the match of the rule with the highest priority, then the highest severity, then the smallest ID.
+func deduplicateMatches(matches []Match) []Match {
+	if len(matches) < 2 {
+		return matches
+	}
+
+	ranked := make([]Match, len(matches))
+	copy(ranked, matches)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return outranks(ranked[i].Rule, ranked[j].Rule)
+	})
+
+	var winners []Match
+	for _, m := range ranked {
+		if !overlapsOtherRule(winners, m) {
+			winners = append(winners, m)
+		}
+	}
+	return winners
+}
+
+// overlapsOtherRule checks if the match overlaps any of the matches of other rules
+func overlapsOtherRule(matches []Match, match Match) bool {
+	for _, m := range matches {
+		if m.Rule.ID != match.Rule.ID && overlaps([]Match{m}, match.Location) {
+			return true
+		}
+	}
+	return false
+}
+
+// deduplicateSpans keeps only one of the matches of different rules or detectors at exactly the same location,
+// ranked in the same way as deduplicateMatches. The order of the matches is kept otherwise.
+func deduplicateSpans(matches []Match) []Match {
+	if len(matches) < 2 {
+		return matches
+	}
+
+	var kept []Match
+	spans := make(map[Location]int)
+	for _, m := range matches {
+		// Secrets decoded from data URIs are different from the raw payloads at the same location
+		if m.decoded != nil {
+			kept = append(kept, m)
+			continue
+		}
+		i, ok := spans[m.Location]
+		if !ok {
+			spans[m.Location] = len(kept)
+			kept = append(kept, m)
+			continue
+		}
+		if outranks(m.Rule, kept[i].Rule) {
+			kept[i] = m
+		}
+	}
+	return kept
+}
+
+// outranks checks if the rule has a higher priority, then a higher severity, then a smaller ID than the other
+func outranks(r, other Rule) bool {
+	if r.Priority != other.Priority {
+		return r.Priority > other.Priority
+	}
+	if s, o := severityIndex(r.Severity), severityIndex(other.Severity); s != o {
+		return s > o
+	}
+	return r.ID < other.ID
+}
@@ -0,0 +1,77 @@
+package secret
+
+import (
+	"github.com/samber/lo"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// Detector is an interface for secret detectors which can't be expressed as a regex rule.
+// Detectors run alongside rules and their results are merged.
+type Detector interface {
+	// Name returns the detector name. It is used as the rule ID if Finding.RuleID is empty.
+	Name() string
+
+	// Detect returns secrets found in the content.
+	Detect(content []byte, path string) []Finding
+}
+
+// Finding represents a secret found by Detector
+type Finding struct {
+	RuleID   string
+	Category types.SecretRuleCategory
+	Title    string
+	Severity string
+	Location Location
+	Context  string // e.g. the flag passing the secret
+}
+
+// RegisterDetector registers a custom detector
+func (s *Scanner) RegisterDetector(d Detector) {
+	s.Detectors = append(s.Detectors, d)
+}
+
+func (s *Scanner) detect(d Detector, args ScanArgs) []Match {
+	var matches []Match
+	for _, f := range d.Detect(args.Content, args.FilePath) {
+		// Ignore invalid locations returned by the detector
+		if f.Location.Start < 0 || f.Location.End > len(args.Content) || f.Location.Start > f.Location.End {
+			continue
+		}
+		rule := Rule{ID: lo.Ternary(f.RuleID == "", d.Name(), f.RuleID)}
+		if s.AllowLocation(rule, args.Content, f.Location) || s.AllowSecret(rule, args.Content, f.Location) {
+			continue
+		}
+		matches = append(matches, Match{
+			Rule: Rule{
+				ID:       rule.ID,
+				Category: f.Category,
+				Title:    f.Title,
+				Severity: f.Severity,
+			},
+			Location: f.Location,
+			Region:   f.Location,
+			Context:  f.Context,
+		})
+	}
+	return matches
+}
+
+// overlaps checks if the location overlaps any of the matches
+func overlaps(matches []Match, loc Location) bool {
+	for _, m := range matches {
+		if m.Location.Start < loc.End && loc.Start < m.Location.End {
+			return true
+		}
+	}
+	return false
+}
+
+// fallbackDetector checks if the detector reports only secrets which are not detected by rules
+func fallbackDetector(d Detector) bool {
+	switch d.(type) {
+	case kubeconfigDetector, secretKeyDetector, genericEntropyDetector, commandLineDetector:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package secret
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// e.g. "func (c *Client) Connect(", "def connect(", "export async function connect(" and "pub fn connect("
+var declarationRegex = regexp.MustCompile(`(?m)^[ \t]*(?:(?:export|default|async|pub|public|private|protected|static)\s+)*` +
+	`(?P<keyword>func|def|function|fn)\b\s*\*?\s*(?:\([^)\n]*\)\s*)?(?P<name>[A-Za-z_$][\w$]*)`)
+
+type declaration struct {
+	offset int
+	label  string
+}
+
+// findDeclarations returns function declarations in the content.
+// It is a language-agnostic heuristic and doesn't know where functions end.
+func findDeclarations(content []byte) []declaration {
+	keywordIndex := declarationRegex.SubexpIndex("keyword")
+	nameIndex := declarationRegex.SubexpIndex("name")
+
+	var declarations []declaration
+	for _, m := range declarationRegex.FindAllSubmatchIndex(content, -1) {
+		declarations = append(declarations, declaration{
+			offset: m[0],
+			label: fmt.Sprintf("%s %s", content[m[2*keywordIndex]:m[2*keywordIndex+1]],
+				content[m[2*nameIndex]:m[2*nameIndex+1]]),
+		})
+	}
+	return declarations
+}
+
+// enclosingFunction returns the label of the nearest declaration before the location
+func enclosingFunction(declarations []declaration, loc Location) string {
+	var label string
+	for _, d := range declarations {
+		if d.offset > loc.Start {
+			break
+		}
+		label = d.label
+	}
+	return label
+}
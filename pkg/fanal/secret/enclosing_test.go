@@ -0,0 +1,65 @@
+package secret_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/secret"
+)
+
+func TestScanner_EnclosingFunction(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		disabled bool
+		want     map[int]string
+	}{
+		{
+			name:     "go",
+			filePath: "testdata/enclosing-function.go",
+			want: map[int]string{
+				3:  "",
+				6:  "func Connect",
+				11: "func newClient",
+			},
+		},
+		{
+			name:     "python",
+			filePath: "testdata/enclosing-function.py",
+			want: map[int]string{
+				3: "def connect",
+			},
+		},
+		{
+			name:     "disabled",
+			filePath: "testdata/enclosing-function.go",
+			disabled: true,
+			want: map[int]string{
+				3:  "",
+				6:  "",
+				11: "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := os.ReadFile(tt.filePath)
+			require.NoError(t, err)
+
+			s := secret.NewScanner(&secret.Config{ReportEnclosingFunction: !tt.disabled})
+			got := s.Scan(secret.ScanArgs{
+				FilePath: tt.filePath,
+				Content:  content,
+			})
+
+			contexts := map[int]string{}
+			for _, finding := range got.Findings {
+				contexts[finding.StartLine] = finding.Context
+			}
+			assert.Equal(t, tt.want, contexts)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package secret
+
+import (
+	"math"
+	"regexp"
+)
+
+// e.g. api_key = "Zm9vYmFyYmF6cXV4cXV1eA", "client_secret": "..." and token: '...'
+var genericSecretRegex = regexp.MustCompile(`(?i)[\w.\-]+["']?\s*(?::=|=>|:|=)\s*["'](?P<secret>[A-Za-z0-9+/=_\-]{20,128})["']`)
+
+// genericEntropyDetector detects random-looking string values assigned to keys.
+// It is a fallback for secrets which are not detected by rules and is enabled by GenericEntropyThreshold.
+type genericEntropyDetector struct {
+	threshold float64
+}
+
+func (genericEntropyDetector) Name() string {
+	return "generic-high-entropy"
+}
+
+func (d genericEntropyDetector) Detect(content []byte, _ string) []Finding {
+	var findings []Finding
+	secretIndex := genericSecretRegex.SubexpIndex("secret")
+	for _, loc := range genericSecretRegex.FindAllSubmatchIndex(content, -1) {
+		start, end := loc[2*secretIndex], loc[2*secretIndex+1]
+		if shannonEntropy(content[start:end]) < d.threshold {
+			continue
+		}
+		findings = append(findings, Finding{
+			Category: CategoryGeneric,
+			Title:    "High entropy string",
+			Severity: "LOW",
+			Location: Location{
+				Start: start,
+				End:   end,
+			},
+		})
+	}
+	return findings
+}
+
+// shannonEntropy returns the Shannon entropy of the bytes in bits per byte
+func shannonEntropy(b []byte) float64 {
+	if len(b) == 0 {
+		return 0
+	}
+
+	var counts [256]int
+	for _, c := range b {
+		counts[c]++
+	}
+
+	var entropy float64
+	for _, count := range counts {
+		if count == 0 {
+			continue
+		}
+		p := float64(count) / float64(len(b))
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
@@ -0,0 +1,73 @@
+package secret_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/secret"
+)
+
+func TestScanner_GenericEntropyThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold float64
+		want      map[int]string
+	}{
+		{
+			name: "generic detector disabled",
+			want: map[int]string{
+				4: "custom-token",
+			},
+		},
+		{
+			name:      "low threshold",
+			threshold: 3.5,
+			want: map[int]string{
+				1: "generic-high-entropy",
+				3: "generic-high-entropy",
+				4: "custom-token",
+			},
+		},
+		{
+			name:      "high threshold",
+			threshold: 4,
+			want: map[int]string{
+				1: "generic-high-entropy",
+				4: "custom-token",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := os.ReadFile("testdata/high-entropy.txt")
+			require.NoError(t, err)
+
+			s := secret.NewScanner(&secret.Config{
+				GenericEntropyThreshold: tt.threshold,
+				CustomRules: []secret.Rule{
+					{
+						ID:       "custom-token",
+						Category: "general",
+						Title:    "Custom Token",
+						Severity: "HIGH",
+						Regex:    secret.MustCompile(`tok_[A-Za-z0-9]+`),
+						Entropy:  3,
+					},
+				},
+			})
+			got := s.Scan(secret.ScanArgs{
+				FilePath: "testdata/high-entropy.txt",
+				Content:  content,
+			})
+
+			ruleIDs := map[int]string{}
+			for _, finding := range got.Findings {
+				ruleIDs[finding.StartLine] = finding.RuleID
+			}
+			assert.Equal(t, tt.want, ruleIDs)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package secret
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// e.g. DB_PASSWORD= and export DB_PASSWORD=
+var envAssignmentRegex = regexp.MustCompile(`(?m)^[ \t]*(?:export[ \t]+)?(?P<key>[A-Za-z_][\w.\-]*)[ \t]*=[ \t]*`)
+
+func isEnvFile(path string) bool {
+	base := filepath.Base(path)
+	return base == ".env" || strings.HasPrefix(base, ".env.") || filepath.Ext(base) == ".env"
+}
+
+// detectEnv inspects the values of credential-bearing keys in .env files.
+// Quoted values may contain spaces and span multiple lines, e.g. KEY="-----BEGIN ...\n...", and are inspected as a whole.
+// Lines inside quoted values are not regarded as assignments. Unquoted values end at a whitespace or an inline comment.
+func (d secretKeyDetector) detectEnv(content []byte) []Finding {
+	var findings []Finding
+	var valueEnd int
+	keyIndex := envAssignmentRegex.SubexpIndex("key")
+	for _, loc := range envAssignmentRegex.FindAllSubmatchIndex(content, -1) {
+		// Skip e.g. "KEY=" on a line of a multiline value
+		if loc[0] < valueEnd {
+			continue
+		}
+		key := string(content[loc[2*keyIndex]:loc[2*keyIndex+1]])
+		start, end := envValue(content, loc[1])
+		valueEnd = end
+		if start == end || !secretKey(d.patterns, key) || !sensitiveValue(key, string(content[start:end])) {
+			continue
+		}
+		findings = append(findings, sensitiveKeyFinding(start, end))
+	}
+	return findings
+}
+
+// envValue returns the location of the value starting at the offset, excluding the quotes.
+// An unterminated quoted value is regarded as unquoted.
+func envValue(content []byte, offset int) (int, int) {
+	if offset < len(content) && (content[offset] == '"' || content[offset] == '\'') {
+		if end := closingQuote(content[offset+1:], content[offset]); end != -1 {
+			return offset + 1, offset + 1 + end
+		}
+		offset++
+	}
+	end := offset
+	for end < len(content) && strings.IndexByte(" \t\r\n#\"'", content[end]) == -1 {
+		end++
+	}
+	return offset, end
+}
+
+// closingQuote returns the index of the closing quote. Quotes escaped by a backslash are skipped in double-quoted values.
+func closingQuote(b []byte, quote byte) int {
+	for i := 0; i < len(b); i++ {
+		switch {
+		case b[i] == '\\' && quote == '"':
+			i++
+		case b[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,37 @@
+package secret
+
+import (
+	"time"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/log"
+)
+
+// Expired checks if the allow rule has expired at the time. Allow rules without Expires never expire.
+func (r AllowRule) Expired(now time.Time) bool {
+	return !r.Expires.IsZero() && !now.Before(r.Expires)
+}
+
+// activeAllowRules splits the allow rules into the active ones and the expired ones.
+// Expired allow rules are warned about so that they are removed from the config.
+func activeAllowRules(rules AllowRules, now time.Time) (AllowRules, []AllowRule) {
+	var active AllowRules
+	var expired []AllowRule
+	for _, r := range rules {
+		if r.Expired(now) {
+			log.Logger.Warnf("The allow rule %q expired on %s and is ignored", r.ID, r.Expires.Format("2006-01-02"))
+			expired = append(expired, r)
+			continue
+		}
+		active = append(active, r)
+	}
+	if len(expired) == 0 {
+		return rules, nil
+	}
+	return active, expired
+}
+
+// ExpiredAllowRules returns the allow rules which had expired when the scanner was created and are ignored.
+// They are still present in the config and should be removed or renewed.
+func (s *Scanner) ExpiredAllowRules() []AllowRule {
+	return s.expiredAllowRules
+}
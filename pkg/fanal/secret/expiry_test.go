@@ -0,0 +1,76 @@
+package secret_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/clock"
+	"github.com/aquasecurity/trivy/pkg/fanal/secret"
+)
+
+func TestScanner_ExpiredAllowRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		now         time.Time
+		wantMatches []string
+		wantExpired []string
+	}{
+		{
+			name: "no allow rules expired",
+			now:  time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "rule allow rule expired",
+			now:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantMatches: []string{
+				`secret="**********"`,
+			},
+			wantExpired: []string{"skip-other"},
+		},
+		{
+			name: "all allow rules expired",
+			now:  time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+			wantMatches: []string{
+				`generic secret line secret="*********"`,
+				`secret="**********"`,
+			},
+			wantExpired: []string{
+				"skip-some",
+				"skip-other",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock.SetFakeTime(t, tt.now)
+
+			c, err := secret.ParseConfig("testdata/allow-expires.yaml")
+			require.NoError(t, err)
+			content, err := os.ReadFile("testdata/secret.txt")
+			require.NoError(t, err)
+
+			s := secret.NewScanner(c)
+			got := s.Scan(secret.ScanArgs{
+				FilePath: "testdata/secret.txt",
+				Content:  content,
+			})
+
+			var matches []string
+			for _, f := range got.Findings {
+				assert.Equal(t, "rule1", f.RuleID)
+				matches = append(matches, f.Match)
+			}
+			assert.ElementsMatch(t, tt.wantMatches, matches)
+
+			expired := lo.Map(s.ExpiredAllowRules(), func(r secret.AllowRule, _ int) string {
+				return r.ID
+			})
+			assert.ElementsMatch(t, tt.wantExpired, expired)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package secret
+
+import (
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+const (
+	ExposureWorldReadable = "world-readable"
+	ExposurePublicPath    = "public-path"
+)
+
+// Directories served by web servers as is
+var publicDirs = []string{"public", "public_html", "static", "www", "wwwroot", "htdocs"}
+
+// exposure returns how the file exposes its secrets
+func (g Global) exposure(args ScanArgs) []string {
+	if !g.ReportExposure {
+		return nil
+	}
+
+	var exposure []string
+	if args.Info != nil && args.Info.Mode().Perm()&0o004 != 0 {
+		exposure = append(exposure, ExposureWorldReadable)
+	}
+
+	dirs := strings.Split(filepath.ToSlash(args.FilePath), "/")
+	for _, dir := range dirs[:len(dirs)-1] {
+		if slices.Contains(publicDirs, dir) {
+			exposure = append(exposure, ExposurePublicPath)
+			break
+		}
+	}
+	return exposure
+}
@@ -0,0 +1,48 @@
+package secret
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// fingerprint returns a stable identifier of the secret detected by the rule in the file.
+// Line numbers are not included so that the fingerprint doesn't change when lines are added above the secret.
+func fingerprint(filePath, ruleID string, secret []byte) string {
+	h := sha256.New()
+	h.Write([]byte(filePath))
+	h.Write([]byte{0})
+	h.Write([]byte(ruleID))
+	h.Write([]byte{0})
+	h.Write(secret)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// StampFirstSeen sets FirstSeen of the findings in the results.
+// Findings with the same fingerprint as findings in the prior results keep the earliest timestamp of them,
+// and the other findings are stamped with now. Fingerprints must be enabled in both scans.
+func StampFirstSeen(results, prior []types.Secret, now time.Time) {
+	firstSeen := make(map[string]time.Time)
+	for _, result := range prior {
+		for _, finding := range result.Findings {
+			if finding.Fingerprint == "" || finding.FirstSeen == nil {
+				continue
+			}
+			if t, ok := firstSeen[finding.Fingerprint]; !ok || finding.FirstSeen.Before(t) {
+				firstSeen[finding.Fingerprint] = *finding.FirstSeen
+			}
+		}
+	}
+
+	for i := range results {
+		for j := range results[i].Findings {
+			finding := &results[i].Findings[j]
+			t, ok := firstSeen[finding.Fingerprint]
+			finding.FirstSeen = lo.ToPtr(lo.Ternary(ok && finding.Fingerprint != "", t, now))
+		}
+	}
+}
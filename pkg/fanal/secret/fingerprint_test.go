@@ -0,0 +1,202 @@
+package secret_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/secret"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func TestScanner_Fingerprints(t *testing.T) {
+	content, err := os.ReadFile("testdata/aws-secrets.txt")
+	require.NoError(t, err)
+
+	scan := func(config *secret.Config, content []byte) []types.SecretFinding {
+		s := secret.NewScanner(config)
+		return s.Scan(secret.ScanArgs{
+			FilePath: "testdata/aws-secrets.txt",
+			Content:  content,
+		}).Findings
+	}
+
+	findings := scan(&secret.Config{Fingerprints: true}, content)
+	require.Len(t, findings, 3)
+
+	fingerprints := map[string]string{}
+	for _, finding := range findings {
+		assert.Len(t, finding.Fingerprint, 64)
+		fingerprints[finding.RuleID] = finding.Fingerprint
+	}
+	assert.Len(t, fingerprints, 3, "fingerprints must be unique")
+
+	t.Run("stable when lines are added", func(t *testing.T) {
+		shifted := append([]byte("# credentials\n\n"), content...)
+		for _, finding := range scan(&secret.Config{Fingerprints: true}, shifted) {
+			assert.Equal(t, fingerprints[finding.RuleID], finding.Fingerprint, finding.RuleID)
+		}
+	})
+
+	t.Run("not populated by default", func(t *testing.T) {
+		for _, finding := range scan(&secret.Config{}, content) {
+			assert.Empty(t, finding.Fingerprint)
+		}
+	})
+
+	t.Run("ignore fingerprints", func(t *testing.T) {
+		got := scan(&secret.Config{
+			IgnoreFingerprints: map[string]struct{}{
+				fingerprints["aws-access-key-id"]: {},
+			},
+		}, content)
+
+		var ruleIDs []string
+		for _, finding := range got {
+			ruleIDs = append(ruleIDs, finding.RuleID)
+
+			// The ignored secret must still be censored
+			for _, line := range finding.Code.Lines {
+				assert.NotContains(t, line.Content, "AKIA0123456789ABCDEF")
+			}
+		}
+		assert.Equal(t, []string{"aws-account-id", "aws-secret-access-key"}, ruleIDs)
+	})
+}
+
+func TestStampFirstSeen(t *testing.T) {
+	firstScan := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	prior := []types.Secret{
+		{
+			FilePath: "config.env",
+			Findings: []types.SecretFinding{
+				{
+					RuleID:      "aws-access-key-id",
+					Fingerprint: "fingerprint1",
+					FirstSeen:   &firstScan,
+				},
+			},
+		},
+	}
+	results := []types.Secret{
+		{
+			FilePath: "config.env",
+			Findings: []types.SecretFinding{
+				{
+					RuleID:      "aws-access-key-id",
+					Fingerprint: "fingerprint1",
+				},
+				{
+					RuleID:      "github-pat",
+					Fingerprint: "fingerprint2",
+				},
+				{
+					RuleID: "slack-web-hook",
+				},
+			},
+		},
+	}
+
+	secret.StampFirstSeen(results, prior, now)
+
+	got := map[string]time.Time{}
+	for _, finding := range results[0].Findings {
+		require.NotNil(t, finding.FirstSeen, finding.RuleID)
+		got[finding.RuleID] = *finding.FirstSeen
+	}
+	assert.Equal(t, map[string]time.Time{
+		"aws-access-key-id": firstScan,
+		"github-pat":        now,
+		"slack-web-hook":    now,
+	}, got)
+}
+
+func TestScanner_TrimSecrets(t *testing.T) {
+	rule := secret.Rule{
+		ID:              "greedy-token",
+		Category:        secret.CategoryGeneric,
+		Title:           "Greedy token",
+		Severity:        "HIGH",
+		Regex:           secret.MustCompile(`token:(?P<secret>[^\n]+)`),
+		SecretGroupName: "secret",
+		Keywords:        []string{"token:"},
+	}
+	scan := func(config *secret.Config, content string) types.SecretFinding {
+		config.CustomRules = []secret.Rule{rule}
+		config.Fingerprints = true
+		config.MatchRegion = true
+		s := secret.NewScanner(config)
+		findings := s.Scan(secret.ScanArgs{
+			FilePath: "config.yaml",
+			Content:  []byte(content),
+		}).Findings
+		require.Len(t, findings, 1)
+		return findings[0]
+	}
+
+	want := scan(&secret.Config{}, "token:s3cr3tV@lue\n")
+
+	tests := []struct {
+		name       string
+		config     *secret.Config
+		content    string
+		wantSecret string
+		wantMatch  string
+		wantStable bool
+	}{
+		{
+			name:       "trailing spaces are trimmed",
+			config:     &secret.Config{TrimSecrets: true},
+			content:    "token: s3cr3tV@lue   \n",
+			wantSecret: "***********",
+			wantMatch:  "token: ***********   ",
+			wantStable: true,
+		},
+		{
+			name:       "quotes are kept without trim-quotes",
+			config:     &secret.Config{TrimSecrets: true},
+			content:    "token: \"s3cr3tV@lue\"\n",
+			wantSecret: "*************",
+			wantMatch:  "token: *************",
+		},
+		{
+			name:       "quotes and whitespace are trimmed",
+			config:     &secret.Config{TrimSecrets: true, TrimQuotes: true},
+			content:    "token: \"s3cr3tV@lue\" \n",
+			wantSecret: "***********",
+			wantMatch:  "token: \"***********\" ",
+			wantStable: true,
+		},
+		{
+			name:       "not trimmed by default",
+			config:     &secret.Config{},
+			content:    "token: s3cr3tV@lue   \n",
+			wantSecret: "***************",
+			wantMatch:  "token:***************",
+		},
+		{
+			name:       "whitespace-only secret is kept",
+			config:     &secret.Config{TrimSecrets: true},
+			content:    "token:    \n",
+			wantSecret: "****",
+			wantMatch:  "token:****",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scan(tt.config, tt.content)
+			assert.Equal(t, tt.wantSecret, got.Secret)
+			assert.Equal(t, tt.wantMatch, got.Match)
+			if tt.wantStable {
+				assert.Equal(t, want.Fingerprint, got.Fingerprint)
+			} else {
+				assert.NotEqual(t, want.Fingerprint, got.Fingerprint)
+			}
+		})
+	}
+}
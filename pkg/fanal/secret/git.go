@@ -0,0 +1,202 @@
+package secret
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// ScanIndex scans the staged contents of files in the git index, i.e. exactly what will be committed.
+// Unstaged changes in the working tree are not scanned.
+func (s *Scanner) ScanIndex(repoPath string) ([]types.Secret, error) {
+	return s.NewSession().ScanIndex(repoPath)
+}
+
+// ScanIndex scans the staged contents of files in the git index which are required by Scanner.Required, like ScanDir.
+func (s *Session) ScanIndex(repoPath string) ([]types.Secret, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, xerrors.Errorf("git open error: %w", err)
+	}
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, xerrors.Errorf("git index error: %w", err)
+	}
+
+	var b *blamer
+	if s.scanner.AttributeAuthor {
+		b = newBlamer(repo)
+	}
+
+	var secrets []types.Secret
+	for _, entry := range idx.Entries {
+		// Skip submodules and symlinks
+		if entry.Mode != filemode.Regular && entry.Mode != filemode.Executable {
+			continue
+		}
+
+		blob, err := repo.BlobObject(entry.Hash)
+		if err != nil {
+			return nil, xerrors.Errorf("git blob error %s: %w", entry.Name, err)
+		}
+		content, ok, err := s.readFile(entry.Name, newBlobInfo(entry, blob.Size), func() ([]byte, error) {
+			return readBlob(blob.Reader)
+		})
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+
+		// Files in the index are tracked by definition
+		if result := s.scanGitFile(b, entry.Name, content, true); len(result.Findings) > 0 || result.Clean {
+			secrets = append(secrets, result)
+		}
+	}
+	return secrets, s.result()
+}
+
+// ScanWorkTree scans files in the working tree, including files which are not tracked by git.
+// Findings have Tracked populated so that untracked files, which may have been left by accident, can be highlighted.
+func (s *Scanner) ScanWorkTree(repoPath string) ([]types.Secret, error) {
+	return s.NewSession().ScanWorkTree(repoPath)
+}
+
+// ScanWorkTree scans files in the working tree which are required by Scanner.Required, like ScanDir.
+func (s *Session) ScanWorkTree(repoPath string) ([]types.Secret, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, xerrors.Errorf("git open error: %w", err)
+	}
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, xerrors.Errorf("git index error: %w", err)
+	}
+
+	var b *blamer
+	if s.scanner.AttributeAuthor {
+		b = newBlamer(repo)
+	}
+	tracked := lo.SliceToMap(idx.Entries, func(entry *index.Entry) (string, struct{}) {
+		return entry.Name, struct{}{}
+	})
+
+	var secrets []types.Secret
+	err = filepath.WalkDir(repoPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == git.GitDirName {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(repoPath, path)
+		if err != nil {
+			return xerrors.Errorf("relative path error: %w", err)
+		}
+		rel = filepath.ToSlash(rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return xerrors.Errorf("file info error %s: %w", rel, err)
+		}
+		content, ok, err := s.readFile(rel, info, func() ([]byte, error) {
+			return os.ReadFile(path)
+		})
+		if err != nil || !ok {
+			return err
+		}
+
+		_, ok = tracked[rel]
+		if result := s.scanGitFile(b, rel, content, ok); len(result.Findings) > 0 || result.Clean {
+			secrets = append(secrets, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("walk error: %w", err)
+	}
+	return secrets, s.result()
+}
+
+// scanGitFile scans the file in the session, populating Tracked and the authors of the findings
+func (s *Session) scanGitFile(b *blamer, filePath string, content []byte, tracked bool) types.Secret {
+	result, _ := s.Scan(ScanArgs{
+		FilePath: filePath,
+		Content:  content,
+	})
+	for i := range result.Findings {
+		result.Findings[i].Tracked = lo.ToPtr(tracked)
+	}
+	b.attribute(&result, content)
+	return result
+}
+
+// blobInfo is the file info of a blob in the git index
+type blobInfo struct {
+	entry *index.Entry
+	size  int64
+}
+
+func newBlobInfo(entry *index.Entry, size int64) os.FileInfo {
+	return blobInfo{
+		entry: entry,
+		size:  size,
+	}
+}
+
+func (i blobInfo) Name() string {
+	return path.Base(i.entry.Name)
+}
+
+func (i blobInfo) Size() int64 {
+	return i.size
+}
+
+func (i blobInfo) Mode() fs.FileMode {
+	mode, err := i.entry.Mode.ToOSFileMode()
+	if err != nil {
+		return 0
+	}
+	return mode
+}
+
+func (i blobInfo) ModTime() time.Time {
+	return i.entry.ModifiedAt
+}
+
+func (i blobInfo) IsDir() bool {
+	return false
+}
+
+func (i blobInfo) Sys() any {
+	return nil
+}
+
+func readBlob(open func() (io.ReadCloser, error)) ([]byte, error) {
+	r, err := open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
@@ -0,0 +1,174 @@
+package secret
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime/debug"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/clock"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+const (
+	gitLabSchemaVersion = "15.0.4"
+	gitLabTimeFormat    = "2006-01-02T15:04:05"
+	gitLabCategory      = "secret_detection"
+
+	// GitLab expects a commit even if the secret is found in the working tree
+	gitLabNoCommit = "0000000"
+)
+
+// GitLab Secret Detection report
+// cf. https://gitlab.com/gitlab-org/security-products/security-report-schemas/-/blob/master/dist/secret-detection-report-format.json
+type gitLabReport struct {
+	Version         string                `json:"version"`
+	Vulnerabilities []gitLabVulnerability `json:"vulnerabilities"`
+	Scan            gitLabScan            `json:"scan"`
+}
+
+type gitLabVulnerability struct {
+	ID          string             `json:"id"`
+	Category    string             `json:"category"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Severity    string             `json:"severity"`
+	Scanner     gitLabScanner      `json:"scanner"`
+	Location    gitLabLocation     `json:"location"`
+	Identifiers []gitLabIdentifier `json:"identifiers"`
+}
+
+type gitLabScanner struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type gitLabLocation struct {
+	File      string       `json:"file"`
+	StartLine int          `json:"start_line"`
+	EndLine   int          `json:"end_line"`
+	Commit    gitLabCommit `json:"commit"`
+}
+
+type gitLabCommit struct {
+	Sha string `json:"sha"`
+}
+
+type gitLabIdentifier struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type gitLabScan struct {
+	Analyzer  gitLabTool `json:"analyzer"`
+	Scanner   gitLabTool `json:"scanner"`
+	Type      string     `json:"type"`
+	StartTime string     `json:"start_time"`
+	EndTime   string     `json:"end_time"`
+	Status    string     `json:"status"`
+}
+
+type gitLabTool struct {
+	ID      string       `json:"id"`
+	Name    string       `json:"name"`
+	Version string       `json:"version"`
+	Vendor  gitLabVendor `json:"vendor"`
+}
+
+type gitLabVendor struct {
+	Name string `json:"name"`
+}
+
+// WriteGitLabReport writes the secrets as a GitLab Secret Detection report so that GitLab CI can ingest them
+func WriteGitLabReport(w io.Writer, secrets []types.Secret) error {
+	tool := gitLabTool{
+		ID:      "trivy",
+		Name:    "Trivy",
+		Version: buildVersion(),
+		Vendor:  gitLabVendor{Name: "Aqua Security"},
+	}
+	now := clock.Now().UTC().Format(gitLabTimeFormat)
+	report := gitLabReport{
+		Version:         gitLabSchemaVersion,
+		Vulnerabilities: []gitLabVulnerability{},
+		Scan: gitLabScan{
+			Analyzer:  tool,
+			Scanner:   tool,
+			Type:      gitLabCategory,
+			StartTime: now,
+			EndTime:   now,
+			Status:    "success",
+		},
+	}
+
+	for _, secret := range secrets {
+		for _, finding := range secret.Findings {
+			report.Vulnerabilities = append(report.Vulnerabilities, gitLabVulnerability{
+				ID:          gitLabID(secret.FilePath, finding),
+				Category:    gitLabCategory,
+				Name:        finding.Title,
+				Description: fmt.Sprintf("%s detected by the rule %s", finding.Title, finding.RuleID),
+				Severity:    gitLabSeverity(finding.Severity),
+				Scanner: gitLabScanner{
+					ID:   tool.ID,
+					Name: tool.Name,
+				},
+				Location: gitLabLocation{
+					File:      secret.FilePath,
+					StartLine: finding.StartLine,
+					EndLine:   finding.EndLine,
+					Commit:    gitLabCommit{Sha: gitLabNoCommit},
+				},
+				Identifiers: []gitLabIdentifier{
+					{
+						Type:  "trivy_rule_id",
+						Name:  "Trivy rule ID " + finding.RuleID,
+						Value: finding.RuleID,
+					},
+				},
+			})
+		}
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	if err := e.Encode(report); err != nil {
+		return xerrors.Errorf("failed to encode the GitLab report: %w", err)
+	}
+	return nil
+}
+
+// gitLabID returns the fingerprint of the finding if available, otherwise an identifier derived from its location
+func gitLabID(filePath string, finding types.SecretFinding) string {
+	if finding.Fingerprint != "" {
+		return finding.Fingerprint
+	}
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%d", filePath, finding.RuleID, finding.StartLine)))
+	return hex.EncodeToString(h[:])
+}
+
+func gitLabSeverity(severity string) string {
+	switch severity {
+	case "CRITICAL":
+		return "Critical"
+	case "HIGH":
+		return "High"
+	case "MEDIUM":
+		return "Medium"
+	case "LOW":
+		return "Low"
+	}
+	return "Unknown"
+}
+
+func buildVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "unknown"
+}
@@ -0,0 +1,87 @@
+package secret_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/clock"
+	"github.com/aquasecurity/trivy/pkg/fanal/secret"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func TestWriteGitLabReport(t *testing.T) {
+	clock.SetFakeTime(t, time.Date(2022, 11, 1, 12, 30, 0, 0, time.UTC))
+
+	secrets := []types.Secret{
+		{
+			FilePath: "config/app.env",
+			Findings: []types.SecretFinding{
+				{
+					RuleID:      "github-pat",
+					Category:    secret.CategoryGitHub,
+					Severity:    "CRITICAL",
+					Title:       "GitHub Personal Access Token",
+					StartLine:   3,
+					EndLine:     3,
+					Fingerprint: "0123abcd",
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, secret.WriteGitLabReport(&buf, secrets))
+
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+
+	assert.Equal(t, "15.0.4", got["version"])
+
+	scan, ok := got["scan"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "secret_detection", scan["type"])
+	assert.Equal(t, "success", scan["status"])
+	assert.Equal(t, "2022-11-01T12:30:00", scan["start_time"])
+	assert.Equal(t, "2022-11-01T12:30:00", scan["end_time"])
+	for _, tool := range []string{"analyzer", "scanner"} {
+		m, ok := scan[tool].(map[string]interface{})
+		require.True(t, ok, tool)
+		assert.Equal(t, "trivy", m["id"])
+		assert.NotEmpty(t, m["version"])
+	}
+
+	vulns, ok := got["vulnerabilities"].([]interface{})
+	require.True(t, ok)
+	want := map[string]interface{}{
+		"id":          "0123abcd",
+		"category":    "secret_detection",
+		"name":        "GitHub Personal Access Token",
+		"description": "GitHub Personal Access Token detected by the rule github-pat",
+		"severity":    "Critical",
+		"scanner": map[string]interface{}{
+			"id":   "trivy",
+			"name": "Trivy",
+		},
+		"location": map[string]interface{}{
+			"file":       "config/app.env",
+			"start_line": float64(3),
+			"end_line":   float64(3),
+			"commit": map[string]interface{}{
+				"sha": "0000000",
+			},
+		},
+		"identifiers": []interface{}{
+			map[string]interface{}{
+				"type":  "trivy_rule_id",
+				"name":  "Trivy rule ID github-pat",
+				"value": "github-pat",
+			},
+		},
+	}
+	assert.Equal(t, []interface{}{want}, vulns)
+}
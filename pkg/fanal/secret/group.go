@@ -0,0 +1,33 @@
+package secret
+
+import (
+	"sort"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// GroupByFile groups findings by file path.
+// Findings for the same file are merged even if they come from different results (e.g. layers),
+// and sorted by line number, rule ID and match so that the order is stable.
+func GroupByFile(secrets []types.Secret) map[string][]types.SecretFinding {
+	groups := map[string][]types.SecretFinding{}
+	for _, s := range secrets {
+		if len(s.Findings) == 0 {
+			continue
+		}
+		groups[s.FilePath] = append(groups[s.FilePath], s.Findings...)
+	}
+
+	for _, findings := range groups {
+		sort.SliceStable(findings, func(i, j int) bool {
+			if findings[i].StartLine != findings[j].StartLine {
+				return findings[i].StartLine < findings[j].StartLine
+			}
+			if findings[i].RuleID != findings[j].RuleID {
+				return findings[i].RuleID < findings[j].RuleID
+			}
+			return findings[i].Match < findings[j].Match
+		})
+	}
+	return groups
+}
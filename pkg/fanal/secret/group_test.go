@@ -0,0 +1,109 @@
+package secret_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/secret"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func TestGroupByFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		secrets []types.Secret
+		want    map[string][]types.SecretFinding
+	}{
+		{
+			name: "multiple files",
+			secrets: []types.Secret{
+				{
+					FilePath: "config.yaml",
+					Findings: []types.SecretFinding{
+						{
+							RuleID:    "github-pat",
+							StartLine: 10,
+						},
+						{
+							RuleID:    "aws-secret-access-key",
+							StartLine: 3,
+						},
+						{
+							RuleID:    "aws-access-key-id",
+							StartLine: 3,
+						},
+					},
+				},
+				{
+					FilePath: "deploy.sh",
+					Findings: []types.SecretFinding{
+						{
+							RuleID:    "slack-access-token",
+							StartLine: 1,
+						},
+					},
+				},
+				{
+					FilePath: "config.yaml",
+					Findings: []types.SecretFinding{
+						{
+							RuleID:    "private-key",
+							StartLine: 5,
+							Layer: types.Layer{
+								DiffID: "sha256:0ea33a93585cf1917ba522b2304634c3073654062d5282c1346322967790ef33",
+							},
+						},
+					},
+				},
+				{
+					FilePath: "clean.txt",
+				},
+			},
+			want: map[string][]types.SecretFinding{
+				"config.yaml": {
+					{
+						RuleID:    "aws-access-key-id",
+						StartLine: 3,
+					},
+					{
+						RuleID:    "aws-secret-access-key",
+						StartLine: 3,
+					},
+					{
+						RuleID:    "private-key",
+						StartLine: 5,
+						Layer: types.Layer{
+							DiffID: "sha256:0ea33a93585cf1917ba522b2304634c3073654062d5282c1346322967790ef33",
+						},
+					},
+					{
+						RuleID:    "github-pat",
+						StartLine: 10,
+					},
+				},
+				"deploy.sh": {
+					{
+						RuleID:    "slack-access-token",
+						StartLine: 1,
+					},
+				},
+			},
+		},
+		{
+			name: "no findings",
+			secrets: []types.Secret{
+				{
+					FilePath: "clean.txt",
+				},
+			},
+			want: map[string][]types.SecretFinding{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := secret.GroupByFile(tt.secrets)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
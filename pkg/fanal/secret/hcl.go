@@ -0,0 +1,87 @@
+package secret
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/exp/slices"
+)
+
+var hclExts = []string{".tf", ".tfvars"}
+
+func isHCL(path string) bool {
+	base := filepath.Base(path)
+	return slices.Contains(hclExts, filepath.Ext(base)) || base == ".terraformrc" || base == "terraform.rc"
+}
+
+// detectHCL inspects string literals assigned to credential-bearing attributes in Terraform files.
+// Interpolations and references such as var.password are not literals and ignored.
+// The attribute path is reported as the context, e.g. "provider.aws.secret_key".
+func (d secretKeyDetector) detectHCL(content []byte, path string) []Finding {
+	file, _ := hclsyntax.ParseConfig(content, path, hcl.InitialPos)
+	if file == nil {
+		return nil
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+	return d.hclBody(content, body, nil)
+}
+
+func (d secretKeyDetector) hclBody(content []byte, body *hclsyntax.Body, parents []string) []Finding {
+	var findings []Finding
+	for _, attr := range body.Attributes {
+		// Variable defaults and outputs are named by their block labels, e.g. variable "db_password" { default = "..." }
+		key := attr.Name
+		if (attr.Name == "default" || attr.Name == "value") && len(parents) > 0 {
+			key = parents[len(parents)-1]
+		}
+		findings = append(findings, d.hclExpr(content, attr.Expr, key, append(slices.Clone(parents), attr.Name))...)
+	}
+	for _, block := range body.Blocks {
+		path := append(append(slices.Clone(parents), block.Type), block.Labels...)
+		findings = append(findings, d.hclBody(content, block.Body, path)...)
+	}
+	return findings
+}
+
+func (d secretKeyDetector) hclExpr(content []byte, expr hclsyntax.Expression, key string, path []string) []Finding {
+	switch e := expr.(type) {
+	case *hclsyntax.ObjectConsExpr:
+		var findings []Finding
+		for _, item := range e.Items {
+			name := hcl.ExprAsKeyword(item.KeyExpr)
+			if name == "" {
+				if v, diags := item.KeyExpr.Value(nil); !diags.HasErrors() && v.Type() == cty.String && v.IsKnown() {
+					name = v.AsString()
+				}
+			}
+			if name != "" {
+				findings = append(findings, d.hclExpr(content, item.ValueExpr, name, append(slices.Clone(path), name))...)
+			}
+		}
+		return findings
+	case *hclsyntax.TemplateExpr:
+		if !e.IsStringLiteral() || !secretKey(d.patterns, key) {
+			return nil
+		}
+		lit := e.Parts[0].(*hclsyntax.LiteralValueExpr)
+		if lit.Val.Type() != cty.String {
+			return nil
+		}
+		value := lit.Val.AsString()
+		start, end := lit.SrcRange.Start.Byte, lit.SrcRange.End.Byte
+		// Skip values with escapes as they are not written as is
+		if !sensitiveValue(key, value) || end > len(content) || string(content[start:end]) != value {
+			return nil
+		}
+		finding := sensitiveKeyFinding(start, end)
+		finding.Context = strings.Join(path, ".")
+		return []Finding{finding}
+	}
+	return nil
+}
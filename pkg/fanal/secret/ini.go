@@ -0,0 +1,62 @@
+package secret
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+
+	"golang.org/x/exp/slices"
+)
+
+var (
+	iniExts = []string{".ini", ".cfg"}
+
+	// e.g. [database]
+	iniSectionRegex = regexp.MustCompile(`^[ \t]*\[(?P<section>[^\]]+)\]`)
+
+	// e.g. password = secret and password: secret. Lines starting with ";" or "#" are comments.
+	iniAssignmentRegex = regexp.MustCompile(`^[ \t]*(?P<key>[^\s;#\[=:][^=:]*?)[ \t]*[=:][ \t]*(?P<value>.*?)[ \t\r]*$`)
+)
+
+func isINI(path string) bool {
+	return slices.Contains(iniExts, filepath.Ext(path))
+}
+
+// detectINI inspects the values of credential-bearing keys in INI files such as php.ini, setup.cfg and .pypirc.
+// Quotes around values are not part of the values.
+// The section and the key are reported as the context, e.g. "database.password".
+func (d secretKeyDetector) detectINI(content []byte) []Finding {
+	var findings []Finding
+	var section string
+	offset := 0
+	keyIndex, valueIndex := iniAssignmentRegex.SubexpIndex("key"), iniAssignmentRegex.SubexpIndex("value")
+	for _, line := range bytes.SplitAfter(content, lineSep) {
+		lineStart := offset
+		offset += len(line)
+		line = bytes.TrimSuffix(line, lineSep)
+
+		if m := iniSectionRegex.FindSubmatch(line); m != nil {
+			section = string(m[1])
+			continue
+		}
+		loc := iniAssignmentRegex.FindSubmatchIndex(line)
+		if loc == nil {
+			continue
+		}
+		key := string(line[loc[2*keyIndex]:loc[2*keyIndex+1]])
+		start, end := lineStart+loc[2*valueIndex], lineStart+loc[2*valueIndex+1]
+		if end-start >= 2 && (content[start] == '"' || content[start] == '\'') && content[end-1] == content[start] {
+			start, end = start+1, end-1
+		}
+		if !secretKey(d.patterns, key) || !sensitiveValue(key, string(content[start:end])) {
+			continue
+		}
+		finding := sensitiveKeyFinding(start, end)
+		finding.Context = key
+		if section != "" {
+			finding.Context = section + "." + key
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
@@ -0,0 +1,231 @@
+package secret
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/log"
+)
+
+var (
+	// Patterns of credential-bearing key names, e.g. DB_PASSWORD, api_key and client.secret.
+	// They are matched case-insensitively against whole segments of the key separated by "_", "." or "-",
+	// so that keys such as "tokenizer" and "secretName" are not regarded as credential-bearing.
+	defaultSecretKeyPatterns = []string{
+		`(^|[_.-])(passw(or)?d|pwd)($|[_.-])`,
+		`(^|[_.-])(secret|token)($|[_.-])`,
+		`(^|[_.-])(api|access|private)_?key($|[_.-])`,
+		`(^|[_.-])credentials?($|[_.-])`,
+	}
+	defaultSecretKeyRegexes = compileSecretKeyPatterns(defaultSecretKeyPatterns)
+
+	// e.g. "password": "secret"
+	jsonKeyValueRegex = regexp.MustCompile(`"(?P<key>[^"\\\n]+)"\s*:\s*"(?P<value>(?:[^"\\\n]|\\.)+)"`)
+
+	// Values referring to other variables, e.g. ${DB_PASSWORD}, $(API_KEY) of Xcode and {{ .Values.password }}
+	referenceRegex = regexp.MustCompile(`^(\$\{?\w+\}?|\$\(\w+\)|\{\{.*\}\}|<.*>)$`)
+
+	// Document separators in YAML streams
+	yamlSeparatorRegex = regexp.MustCompile(`(?m)^---(?:[ \t].*)?$`)
+
+	// e.g. 3600000 and 1.5
+	numberRegex = regexp.MustCompile(`^[+-]?\d+(\.\d+)?$`)
+)
+
+const (
+	minSensitiveValueLength = 6
+
+	// Numbers such as timeouts and lengths are sensitive only if they look random, e.g. long numeric passwords
+	minNumericValueEntropy = 3.0
+)
+
+// compileSecretKeyPatterns compiles the key name patterns case-insensitively. Invalid patterns are ignored.
+func compileSecretKeyPatterns(patterns []string) []*regexp.Regexp {
+	var regexes []*regexp.Regexp
+	for _, pattern := range patterns {
+		regex, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			log.Logger.Warnf("Invalid secret key pattern %q: %s", pattern, err)
+			continue
+		}
+		regexes = append(regexes, regex)
+	}
+	return regexes
+}
+
+// secretKeyDetector detects values of credential-bearing keys in structured files such as .env, JSON, YAML, INI, Terraform,
+// systemd unit and property list files.
+// It is a fallback for secrets which are not detected by rules and is enabled by ScanSecretKeys.
+type secretKeyDetector struct {
+	patterns []*regexp.Regexp
+}
+
+func (secretKeyDetector) Name() string {
+	return "sensitive-key"
+}
+
+func (d secretKeyDetector) Detect(content []byte, path string) []Finding {
+	if isHCL(path) {
+		return d.detectHCL(content, path)
+	}
+	if isSystemdUnit(path) {
+		return d.detectSystemd(content)
+	}
+	if filepath.Ext(path) == ".plist" {
+		return d.detectPlist(content, path)
+	}
+	if isEnvFile(path) {
+		return d.detectEnv(content)
+	}
+	if isINI(path) {
+		return d.detectINI(content)
+	}
+	if ext := filepath.Ext(path); ext == ".yaml" || ext == ".yml" {
+		return d.detectYAML(content)
+	}
+
+	regex := keyValueRegex(path)
+	if regex == nil {
+		return nil
+	}
+
+	var findings []Finding
+	keyIndex, valueIndex := regex.SubexpIndex("key"), regex.SubexpIndex("value")
+	for _, loc := range regex.FindAllSubmatchIndex(content, -1) {
+		key := string(content[loc[2*keyIndex]:loc[2*keyIndex+1]])
+		start, end := loc[2*valueIndex], loc[2*valueIndex+1]
+		if !secretKey(d.patterns, key) || !sensitiveValue(key, string(content[start:end])) {
+			continue
+		}
+		findings = append(findings, sensitiveKeyFinding(start, end))
+	}
+	return findings
+}
+
+// detectYAML parses each document in the YAML stream separately so that a malformed document doesn't hide
+// secrets in the others. Locations are relative to the whole stream.
+func (d secretKeyDetector) detectYAML(content []byte) []Finding {
+	var findings []Finding
+	start := 0
+	for _, sep := range append(yamlSeparatorRegex.FindAllIndex(content, -1), []int{len(content), len(content)}) {
+		doc := content[start:sep[0]]
+		offset := start
+		start = sep[1]
+
+		var node yaml.Node
+		if err := yaml.Unmarshal(doc, &node); err != nil {
+			log.Logger.Debugf("YAML document parse error: %s", err)
+			continue
+		}
+		for _, loc := range d.yamlValues(doc, &node) {
+			findings = append(findings, sensitiveKeyFinding(offset+loc.Start, offset+loc.End))
+		}
+	}
+	return findings
+}
+
+// yamlValues returns the locations of scalar values of credential-bearing keys in the document
+func (d secretKeyDetector) yamlValues(doc []byte, node *yaml.Node) []Location {
+	var locs []Location
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, n := range node.Content {
+			locs = append(locs, d.yamlValues(doc, n)...)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if value.Kind != yaml.ScalarNode {
+				locs = append(locs, d.yamlValues(doc, value)...)
+				continue
+			}
+			if !secretKey(d.patterns, key.Value) || !sensitiveValue(key.Value, value.Value) {
+				continue
+			}
+			if loc, ok := yamlScalarLocation(doc, value); ok {
+				locs = append(locs, loc)
+			}
+		}
+	}
+	return locs
+}
+
+// yamlScalarLocation returns the location of the scalar in the document.
+// It returns false if the scalar is not written as is, e.g. block scalars and quoted scalars with escapes.
+func yamlScalarLocation(doc []byte, node *yaml.Node) (Location, bool) {
+	lines := bytes.SplitAfter(doc, []byte("\n"))
+	if node.Line < 1 || node.Line > len(lines) {
+		return Location{}, false
+	}
+
+	start := len(bytes.Join(lines[:node.Line-1], nil))
+	line := lines[node.Line-1]
+	// Columns are counted in characters
+	for i := 1; i < node.Column && len(line) > 0; i++ {
+		_, size := utf8.DecodeRune(line)
+		line = line[size:]
+		start += size
+	}
+	if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 && len(line) > 0 {
+		line = line[1:]
+		start++
+	}
+
+	if !bytes.HasPrefix(line, []byte(node.Value)) {
+		return Location{}, false
+	}
+	return Location{
+		Start: start,
+		End:   start + len(node.Value),
+	}, true
+}
+
+func sensitiveKeyFinding(start, end int) Finding {
+	return Finding{
+		Category: CategoryGeneric,
+		Title:    "Value of a credential-bearing key",
+		Severity: "MEDIUM",
+		Location: Location{
+			Start: start,
+			End:   end,
+		},
+	}
+}
+
+// keyValueRegex returns the regex of key/value pairs for the file type. It returns nil for unstructured files.
+func keyValueRegex(path string) *regexp.Regexp {
+	if filepath.Ext(path) == ".json" {
+		return jsonKeyValueRegex
+	}
+	return nil
+}
+
+func secretKey(patterns []*regexp.Regexp, key string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// sensitiveValue checks if the value looks like a credential rather than a reference to other variables,
+// the key itself, e.g. "password: password", or a number with low entropy, e.g. "token_expiry: 3600000"
+func sensitiveValue(key, value string) bool {
+	value = strings.TrimSpace(value)
+	if numberRegex.MatchString(value) && shannonEntropy([]byte(value)) < minNumericValueEntropy {
+		return false
+	}
+	return len(value) >= minSensitiveValueLength && !referenceRegex.MatchString(value) && !valueIsKey(key, value)
+}
+
+// valueIsKey checks if the value is the key name, ignoring case and surrounding quotes
+func valueIsKey(key, value string) bool {
+	key = strings.TrimSpace(key)
+	return key != "" && strings.EqualFold(strings.Trim(strings.TrimSpace(value), `"'`), key)
+}
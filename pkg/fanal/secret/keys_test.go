@@ -0,0 +1,585 @@
+package secret_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/secret"
+)
+
+func TestScanner_SecretKeyPatterns(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputFilePath string
+		patterns      []string
+		want          []string
+	}{
+		{
+			name:          "json with default patterns",
+			inputFilePath: "testdata/service.json",
+			want: []string{
+				`  "db_password": "**************",`,
+			},
+		},
+		{
+			name:          "json with custom patterns",
+			inputFilePath: "testdata/service.json",
+			patterns:      []string{`_pin$`, `^auth_`},
+			want: []string{
+				`  "service_pin": "************",`,
+				`  "auth_header": "*******************",`,
+			},
+		},
+		{
+			name:          "env with default patterns",
+			inputFilePath: "testdata/service.env",
+			want: []string{
+				`DB_PASSWORD=**************`,
+			},
+		},
+		{
+			name:          "env with custom patterns",
+			inputFilePath: "testdata/service.env",
+			patterns:      []string{`_pin$`, `^auth_`},
+			want: []string{
+				`SERVICE_PIN=************`,
+				`export AUTH_HEADER="*******************"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := os.ReadFile(tt.inputFilePath)
+			require.NoError(t, err)
+
+			s := secret.NewScanner(&secret.Config{
+				ScanSecretKeys:    true,
+				SecretKeyPatterns: tt.patterns,
+			})
+			got := s.Scan(secret.ScanArgs{
+				FilePath: tt.inputFilePath,
+				Content:  content,
+			})
+
+			var matches []string
+			for _, finding := range got.Findings {
+				assert.Equal(t, "sensitive-key", finding.RuleID)
+				matches = append(matches, finding.Match)
+			}
+			assert.ElementsMatch(t, tt.want, matches)
+		})
+	}
+}
+
+func TestScanner_MultiDocumentYAML(t *testing.T) {
+	content, err := os.ReadFile("testdata/multi-doc.yaml")
+	require.NoError(t, err)
+
+	s := secret.NewScanner(&secret.Config{ScanSecretKeys: true})
+	got := s.Scan(secret.ScanArgs{
+		FilePath: "testdata/multi-doc.yaml",
+		Content:  content,
+	})
+
+	// The malformed third document is skipped without affecting the others
+	want := map[int]string{
+		14: `  db_password: "***************"`,
+		18: `      password: **************`,
+		23: `access_key: ****************`,
+	}
+	lines := map[int]string{}
+	for _, finding := range got.Findings {
+		assert.Equal(t, "sensitive-key", finding.RuleID)
+		lines[finding.StartLine] = finding.Match
+	}
+	assert.Equal(t, want, lines)
+}
+
+func TestScanner_Terraform(t *testing.T) {
+	type finding struct {
+		Line    int
+		Context string
+	}
+	tests := []struct {
+		name          string
+		inputFilePath string
+		want          []finding
+	}{
+		{
+			name:          "tfvars",
+			inputFilePath: "testdata/terraform.tfvars",
+			want: []finding{
+				{
+					Line:    2,
+					Context: "db_password",
+				},
+				{
+					Line:    7,
+					Context: "tags.service_token",
+				},
+			},
+		},
+		{
+			name:          "provider and variable blocks",
+			inputFilePath: "testdata/main.tf",
+			want: []finding{
+				{
+					Line:    3,
+					Context: "provider.aws.secret_key",
+				},
+				{
+					Line:    8,
+					Context: "variable.db_password.default",
+				},
+			},
+		},
+		{
+			name:          "terraformrc",
+			inputFilePath: "testdata/.terraformrc",
+			want: []finding{
+				{
+					Line:    2,
+					Context: "credentials.app.terraform.io.token",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := os.ReadFile(tt.inputFilePath)
+			require.NoError(t, err)
+
+			s := secret.NewScanner(&secret.Config{ScanSecretKeys: true})
+			got := s.Scan(secret.ScanArgs{
+				FilePath: tt.inputFilePath,
+				Content:  content,
+			})
+
+			var findings []finding
+			for _, f := range got.Findings {
+				findings = append(findings, finding{
+					Line:    f.StartLine,
+					Context: f.Context,
+				})
+			}
+			assert.ElementsMatch(t, tt.want, findings)
+		})
+	}
+}
+
+func TestScanner_SystemdUnit(t *testing.T) {
+	type finding struct {
+		Line    int
+		Match   string
+		Context string
+	}
+	tests := []struct {
+		name          string
+		inputFilePath string
+		want          []finding
+	}{
+		{
+			name:          "unit file",
+			inputFilePath: "testdata/app.service",
+			want: []finding{
+				{
+					Line:    7,
+					Match:   `Environment=API_TOKEN=**************** "DB_PASSWORD=*********************"`,
+					Context: "Service.Environment.API_TOKEN",
+				},
+				{
+					Line:    7,
+					Match:   `Environment=API_TOKEN=**************** "DB_PASSWORD=*********************"`,
+					Context: "Service.Environment.DB_PASSWORD",
+				},
+			},
+		},
+		{
+			name:          "drop-in",
+			inputFilePath: "testdata/app.service.d/override.conf",
+			want: []finding{
+				{
+					Line:    2,
+					Match:   `Environment="SLACK_TOKEN=**************************"`,
+					Context: "Service.Environment.SLACK_TOKEN",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := os.ReadFile(tt.inputFilePath)
+			require.NoError(t, err)
+
+			s := secret.NewScanner(&secret.Config{ScanSecretKeys: true})
+			got := s.Scan(secret.ScanArgs{
+				FilePath: tt.inputFilePath,
+				Content:  content,
+			})
+
+			var findings []finding
+			for _, f := range got.Findings {
+				findings = append(findings, finding{
+					Line:    f.StartLine,
+					Match:   f.Match,
+					Context: f.Context,
+				})
+			}
+			assert.ElementsMatch(t, tt.want, findings)
+		})
+	}
+}
+
+func TestScanner_INI(t *testing.T) {
+	content, err := os.ReadFile("testdata/app.ini")
+	require.NoError(t, err)
+
+	s := secret.NewScanner(&secret.Config{ScanSecretKeys: true})
+	got := s.Scan(secret.ScanArgs{
+		FilePath: "testdata/app.ini",
+		Content:  content,
+	})
+
+	type finding struct {
+		Line    int
+		Match   string
+		Context string
+	}
+	want := []finding{
+		{
+			Line:    2,
+			Match:   "api_token = ****************",
+			Context: "api_token",
+		},
+		{
+			Line:    6,
+			Match:   `password = "**************"`,
+			Context: "database.password",
+		},
+		{
+			Line:    11,
+			Match:   "smtp_password: *************",
+			Context: "mail.smtp_password",
+		},
+	}
+	var findings []finding
+	for _, f := range got.Findings {
+		assert.Equal(t, "sensitive-key", f.RuleID)
+		findings = append(findings, finding{
+			Line:    f.StartLine,
+			Match:   f.Match,
+			Context: f.Context,
+		})
+	}
+	assert.ElementsMatch(t, want, findings)
+}
+
+func TestScanner_ScanSecretKeys(t *testing.T) {
+	tests := []struct {
+		name           string
+		filePath       string
+		content        string
+		scanSecretKeys bool
+		want           []string
+	}{
+		{
+			name:           "enabled",
+			filePath:       ".env",
+			content:        "DB_PASSWORD=Sup3rS3cr3t!\n",
+			scanSecretKeys: true,
+			want:           []string{"DB_PASSWORD=************"},
+		},
+		{
+			name:     "disabled by default",
+			filePath: ".env",
+			content:  "DB_PASSWORD=Sup3rS3cr3t!\n",
+		},
+		{
+			name:           "random number",
+			filePath:       ".env",
+			content:        "DB_PASSWORD=8273619450\n",
+			scanSecretKeys: true,
+			want:           []string{"DB_PASSWORD=**********"},
+		},
+		{
+			name:           "keyword in a camel case key",
+			filePath:       "deploy/ingress.yaml",
+			content:        "secretName: my-tls-cert\n",
+			scanSecretKeys: true,
+		},
+		{
+			name:           "keyword as a prefix of a word",
+			filePath:       "tokenizer_config.json",
+			content:        `{"tokenizer": "wordpiece"}`,
+			scanSecretKeys: true,
+		},
+		{
+			name:           "keyword followed by a word",
+			filePath:       "settings.json",
+			content:        `{"secretStorage": "keychain"}`,
+			scanSecretKeys: true,
+		},
+		{
+			name:           "duration",
+			filePath:       ".env",
+			content:        "TOKEN_EXPIRY=3600000\n",
+			scanSecretKeys: true,
+		},
+		{
+			name:           "length",
+			filePath:       ".env",
+			content:        "PASSWORD_MIN_LENGTH=123456\n",
+			scanSecretKeys: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := secret.NewScanner(&secret.Config{ScanSecretKeys: tt.scanSecretKeys})
+			got := s.Scan(secret.ScanArgs{
+				FilePath: tt.filePath,
+				Content:  []byte(tt.content),
+			})
+
+			var matches []string
+			for _, finding := range got.Findings {
+				matches = append(matches, finding.Match)
+			}
+			assert.Equal(t, tt.want, matches)
+		})
+	}
+}
+
+func TestScanner_Plist(t *testing.T) {
+	type finding struct {
+		Line    int
+		Match   string
+		Context string
+	}
+	tests := []struct {
+		name     string
+		filePath string
+		content  []byte
+		patterns []string
+		want     []finding
+	}{
+		{
+			name:     "XML property list",
+			filePath: "testdata/Info.plist",
+			want: []finding{
+				{
+					Line:    8,
+					Match:   "\t<string>********************</string>",
+					Context: "APIKey",
+				},
+			},
+		},
+		{
+			name:     "camel case keys with custom patterns",
+			filePath: "testdata/Info.plist",
+			patterns: []string{`secret$`},
+			want: []finding{
+				{
+					Line:    12,
+					Match:   "\t\t<string>****************</string>",
+					Context: "ClientSecret",
+				},
+			},
+		},
+		{
+			name:     "binary property list",
+			filePath: "Info.plist",
+			content:  []byte("bplist00\xd1\x01\x02VAPIKey_\x10\x14k3yF0rTh3Ex4mpleApp9"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := tt.content
+			if content == nil {
+				var err error
+				content, err = os.ReadFile(tt.filePath)
+				require.NoError(t, err)
+			}
+
+			s := secret.NewScanner(&secret.Config{
+				ScanSecretKeys:    true,
+				SecretKeyPatterns: tt.patterns,
+			})
+			got := s.Scan(secret.ScanArgs{
+				FilePath: tt.filePath,
+				Content:  content,
+			})
+
+			var findings []finding
+			for _, f := range got.Findings {
+				findings = append(findings, finding{
+					Line:    f.StartLine,
+					Match:   f.Match,
+					Context: f.Context,
+				})
+			}
+			assert.ElementsMatch(t, tt.want, findings)
+		})
+	}
+}
+
+func TestScanner_EnvValues(t *testing.T) {
+	content, err := os.ReadFile("testdata/multiline.env")
+	require.NoError(t, err)
+
+	s := secret.NewScanner(&secret.Config{
+		ScanSecretKeys: true,
+		MatchRegion:    true,
+	})
+	got := s.Scan(secret.ScanArgs{
+		FilePath: "testdata/multiline.env",
+		Content:  content,
+	})
+
+	type finding struct {
+		StartLine int
+		EndLine   int
+		Secret    string
+	}
+	want := []finding{
+		{
+			StartLine: 2,
+			EndLine:   3,
+			Secret:    "*******************\n********************",
+		},
+		{
+			StartLine: 4,
+			EndLine:   4,
+			Secret:    "****************************",
+		},
+		{
+			StartLine: 7,
+			EndLine:   7,
+			Secret:    "*************************",
+		},
+		// "DB_PASSWORD=" inside the value is not an assignment
+		{
+			StartLine: 8,
+			EndLine:   10,
+			Secret:    "***************\n*********************************\n***************",
+		},
+	}
+	var findings []finding
+	for _, f := range got.Findings {
+		assert.Equal(t, "sensitive-key", f.RuleID)
+		findings = append(findings, finding{
+			StartLine: f.StartLine,
+			EndLine:   f.EndLine,
+			Secret:    f.Secret,
+		})
+	}
+	assert.ElementsMatch(t, want, findings)
+}
+
+func TestScanner_ValueIsKey(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputFilePath string
+		rules         []secret.Rule
+		want          []string
+	}{
+		{
+			name:          "sensitive key",
+			inputFilePath: "testdata/value-is-key.yaml",
+			want: []string{
+				`  password: **********`,
+			},
+		},
+		{
+			name:          "rule with key group",
+			inputFilePath: "testdata/value-is-key.txt",
+			rules: []secret.Rule{
+				{
+					ID:              "token",
+					Category:        secret.CategoryGeneric,
+					Title:           "Token",
+					Severity:        "HIGH",
+					Regex:           secret.MustCompile(`(?P<key>token) = "(?P<secret>[^"]+)"`),
+					SecretGroupName: "secret",
+				},
+			},
+			want: []string{
+				`token = "**********"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := os.ReadFile(tt.inputFilePath)
+			require.NoError(t, err)
+
+			s := secret.NewScanner(&secret.Config{
+				ScanSecretKeys: true,
+				CustomRules:    tt.rules,
+				DisableRuleIDs: []string{"generic-high-entropy-secret"},
+			})
+			got := s.Scan(secret.ScanArgs{
+				FilePath: tt.inputFilePath,
+				Content:  content,
+			})
+
+			var matches []string
+			for _, finding := range got.Findings {
+				matches = append(matches, finding.Match)
+			}
+			assert.ElementsMatch(t, tt.want, matches)
+		})
+	}
+}
+
+func TestScanner_Kubeconfig(t *testing.T) {
+	content, err := os.ReadFile("testdata/kubeconfig.yaml")
+	require.NoError(t, err)
+
+	type finding struct {
+		Line     int
+		Category string
+		Context  string
+	}
+	want := []finding{
+		{
+			Line:     22,
+			Category: "Kubernetes",
+			Context:  "users.admin.client-key-data (cluster prod)",
+		},
+		{
+			Line:     25,
+			Category: "Kubernetes",
+			Context:  "users.deployer.token (cluster prod)",
+		},
+		{
+			Line:     29,
+			Category: "Kubernetes",
+			Context:  "users.dev.password",
+		},
+	}
+
+	// Certificates, references and the other fields are not reported
+	for _, filePath := range []string{"testdata/kubeconfig.yaml", "home/user/.kube/config", "deploy/admin.kubeconfig"} {
+		t.Run(filePath, func(t *testing.T) {
+			s := secret.NewScanner(&secret.Config{})
+			got := s.Scan(secret.ScanArgs{
+				FilePath: filePath,
+				Content:  content,
+			})
+
+			var findings []finding
+			for _, f := range got.Findings {
+				assert.Equal(t, "kubeconfig", f.RuleID)
+				findings = append(findings, finding{
+					Line:     f.StartLine,
+					Category: string(f.Category),
+					Context:  f.Context,
+				})
+			}
+			assert.ElementsMatch(t, want, findings)
+		})
+	}
+}
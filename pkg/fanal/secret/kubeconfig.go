@@ -0,0 +1,112 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/log"
+)
+
+// Kubeconfig files in YAML, e.g. "kind: Config" of ~/.kube/config
+var kubeconfigKindRegex = regexp.MustCompile(`(?m)^kind:[ \t]*["']?Config["']?[ \t]*$`)
+
+type kubeconfig struct {
+	Users []struct {
+		Name string    `yaml:"name"`
+		User yaml.Node `yaml:"user"`
+	} `yaml:"users"`
+	Contexts []struct {
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+}
+
+// isKubeconfig checks if the file is a kubeconfig by its name, e.g. ~/.kube/config and *.kubeconfig, or by its kind for YAML files
+func isKubeconfig(path string, content []byte) bool {
+	base := filepath.Base(path)
+	switch {
+	case base == "kubeconfig" || filepath.Ext(base) == ".kubeconfig":
+		return true
+	case base == "config" && filepath.Base(filepath.Dir(path)) == ".kube":
+		return true
+	case filepath.Ext(base) == ".yaml" || filepath.Ext(base) == ".yml":
+		return kubeconfigKindRegex.Match(content)
+	}
+	return false
+}
+
+// kubeconfigDetector detects the tokens, passwords and client keys of users in kubeconfig files.
+// client-key-data is reported only if it is a base64-encoded private key.
+// The user and the clusters of the contexts using it are reported as the context, e.g. "users.admin.token (cluster prod)".
+type kubeconfigDetector struct{}
+
+func (kubeconfigDetector) Name() string {
+	return "kubeconfig"
+}
+
+func (kubeconfigDetector) Detect(content []byte, path string) []Finding {
+	if !isKubeconfig(path, content) {
+		return nil
+	}
+
+	var config kubeconfig
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		log.Logger.Debugf("Kubeconfig parse error: %s", err)
+		return nil
+	}
+
+	clusters := make(map[string][]string)
+	for _, c := range config.Contexts {
+		clusters[c.Context.User] = append(clusters[c.Context.User], c.Context.Cluster)
+	}
+
+	var findings []Finding
+	for _, user := range config.Users {
+		if user.User.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(user.User.Content); i += 2 {
+			key, value := user.User.Content[i], user.User.Content[i+1]
+			if value.Kind != yaml.ScalarNode || !kubeconfigCredential(key.Value, value.Value) {
+				continue
+			}
+			loc, ok := yamlScalarLocation(content, value)
+			if !ok {
+				continue
+			}
+
+			context := fmt.Sprintf("users.%s.%s", user.Name, key.Value)
+			if c := clusters[user.Name]; len(c) > 0 {
+				context += fmt.Sprintf(" (cluster %s)", strings.Join(c, ", "))
+			}
+			findings = append(findings, Finding{
+				Category: CategoryKubernetes,
+				Title:    "Kubernetes credential in kubeconfig",
+				Severity: "HIGH",
+				Location: loc,
+				Context:  context,
+			})
+		}
+	}
+	return findings
+}
+
+// kubeconfigCredential checks if the value of the user field is a credential. Client keys are base64-encoded PEM.
+func kubeconfigCredential(field, value string) bool {
+	switch field {
+	case "token", "password":
+		return sensitiveValue(field, value)
+	case "client-key-data":
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+		return err == nil && bytes.Contains(key, []byte("PRIVATE KEY-----"))
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package secret
+
+import (
+	"sort"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+var sensitiveKeyRule = Rule{
+	ID:       "sensitive-key",
+	Category: CategoryGeneric,
+	Title:    "Value of a credential-bearing key",
+	Severity: "MEDIUM",
+}
+
+// ScanKV scans the values of a parsed configuration, e.g. environment variables and config maps.
+// Values are matched by rules along with their keys, and values of credential-bearing keys are reported
+// even if no rule matches. Findings have the key in the context instead of line numbers.
+func (s *Scanner) ScanKV(source string, kv map[string]string) []types.SecretFinding {
+	keys := make([]string, 0, len(kv))
+	for key := range kv {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var findings []types.SecretFinding
+	for _, key := range keys {
+		value := kv[key]
+		content := []byte(key + "=" + value)
+		result := s.Scan(ScanArgs{
+			FilePath: source,
+			Content:  content,
+		})
+
+		kvFindings := result.Findings
+		if len(kvFindings) == 0 && s.sensitiveKV(key, value) {
+			loc := Location{Start: len(key) + 1, End: len(content)}
+			finding := toFinding(sensitiveKeyRule, loc, censorLocation(loc, content))
+			if !s.suppressed(s.transformPath(source), value, []byte(value), &finding) {
+				kvFindings = append(kvFindings, finding)
+			}
+		}
+
+		for _, finding := range kvFindings {
+			// Lines are meaningless as values are not in a file
+			finding.StartLine, finding.EndLine = 0, 0
+			finding.Code = types.Code{}
+			finding.Context = key
+			findings = append(findings, finding)
+		}
+	}
+	s.index(findings)
+	return findings
+}
+
+// sensitiveKV checks if the value of the key looks like a credential
+func (s *Scanner) sensitiveKV(key, value string) bool {
+	return secretKey(s.SecretKeyPatterns, key) && sensitiveValue(key, value) &&
+		!s.truncated([]byte(value), Location{End: len(value)})
+}
@@ -0,0 +1,62 @@
+package secret
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// languages maps file extensions to the languages or the file types reported with ReportLanguage
+var languages = map[string]string{
+	".go":         "go",
+	".py":         "python",
+	".rb":         "ruby",
+	".js":         "javascript",
+	".mjs":        "javascript",
+	".cjs":        "javascript",
+	".jsx":        "javascript",
+	".ts":         "typescript",
+	".tsx":        "typescript",
+	".java":       "java",
+	".kt":         "kotlin",
+	".scala":      "scala",
+	".cs":         "csharp",
+	".php":        "php",
+	".rs":         "rust",
+	".swift":      "swift",
+	".c":          "c",
+	".h":          "c",
+	".cc":         "cpp",
+	".cpp":        "cpp",
+	".hpp":        "cpp",
+	".sh":         "shell",
+	".bash":       "shell",
+	".zsh":        "shell",
+	".ksh":        "shell",
+	".ps1":        "powershell",
+	".sql":        "sql",
+	".yaml":       "yaml",
+	".yml":        "yaml",
+	".json":       "json",
+	".toml":       "toml",
+	".xml":        "xml",
+	".ini":        "ini",
+	".properties": "properties",
+	".tf":         "hcl",
+	".tfvars":     "hcl",
+	".env":        "dotenv",
+	".html":       "html",
+	".md":         "markdown",
+}
+
+// language returns the language of the file by its extension, or by its name for files without extensions such as
+// Dockerfile and .env. It returns an empty string for unknown files.
+func language(filePath string) string {
+	base := filepath.Base(filePath)
+	switch {
+	case base == "Dockerfile" || strings.HasPrefix(base, "Dockerfile."):
+		return "dockerfile"
+	case base == ".env" || strings.HasPrefix(base, ".env."):
+		return "dotenv"
+	}
+	return languages[strings.ToLower(filepath.Ext(base))]
+}
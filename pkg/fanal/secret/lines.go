@@ -0,0 +1,34 @@
+package secret
+
+import (
+	"bytes"
+	"strings"
+)
+
+// splitLines splits the content into lines. The line break at the end of the content doesn't make an empty last line.
+func splitLines(content []byte) []string {
+	return strings.Split(string(bytes.TrimSuffix(content, lineSep)), string(lineSep))
+}
+
+// surroundingLines returns up to n lines above the start line and below the end line, which are 1-based.
+// Fewer lines are returned at the beginning and the end of the file.
+func surroundingLines(lines []string, startLine, endLine, n int) ([]string, []string) {
+	beforeStart := startLine - 1 - n
+	if beforeStart < 0 {
+		beforeStart = 0
+	}
+	afterEnd := endLine + n
+	if afterEnd > len(lines) {
+		afterEnd = len(lines)
+	}
+
+	// Copy the lines not to share them between findings
+	var before, after []string
+	if startLine-1 > beforeStart {
+		before = append(before, lines[beforeStart:startLine-1]...)
+	}
+	if afterEnd > endLine {
+		after = append(after, lines[endLine:afterEnd]...)
+	}
+	return before, after
+}
@@ -0,0 +1,77 @@
+package secret
+
+import (
+	"bytes"
+	"sort"
+)
+
+// mergeMultilineMatches merges matches of the same rule whose line ranges overlap into one match spanning all of them,
+// e.g. nested PEM blocks matched several times by a multiline rule. Matches on a single line are merged only with
+// multiline matches so that distinct secrets on the same line are still reported separately.
+func mergeMultilineMatches(content []byte, matches []Match) []Match {
+	if len(matches) < 2 {
+		return matches
+	}
+
+	type span struct {
+		match              Match
+		startLine, endLine int
+	}
+	var spans []span
+	var merged []Match
+	for _, m := range matches {
+		// Secrets decoded from data URIs are located at the payload
+		if m.decoded != nil {
+			merged = append(merged, m)
+			continue
+		}
+		spans = append(spans, span{
+			match:     m,
+			startLine: bytes.Count(content[:m.Location.Start], lineSep),
+			endLine:   bytes.Count(content[:lastIndex(m.Location)], lineSep),
+		})
+	}
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].match.Rule.ID != spans[j].match.Rule.ID {
+			return spans[i].match.Rule.ID < spans[j].match.Rule.ID
+		}
+		return spans[i].match.Location.Start < spans[j].match.Location.Start
+	})
+
+	for i := 0; i < len(spans); i++ {
+		cur := spans[i]
+		for i+1 < len(spans) {
+			next := spans[i+1]
+			multiline := cur.startLine != cur.endLine || next.startLine != next.endLine
+			if next.match.Rule.ID != cur.match.Rule.ID || next.startLine > cur.endLine || !multiline {
+				break
+			}
+			cur.match.Location = unionLocation(cur.match.Location, next.match.Location)
+			cur.match.Region = unionLocation(cur.match.Region, next.match.Region)
+			if next.endLine > cur.endLine {
+				cur.endLine = next.endLine
+			}
+			i++
+		}
+		merged = append(merged, cur.match)
+	}
+	return merged
+}
+
+// lastIndex returns the index of the last byte in the location
+func lastIndex(loc Location) int {
+	if loc.End > loc.Start {
+		return loc.End - 1
+	}
+	return loc.Start
+}
+
+func unionLocation(a, b Location) Location {
+	if b.Start < a.Start {
+		a.Start = b.Start
+	}
+	if b.End > a.End {
+		a.End = b.End
+	}
+	return a
+}
@@ -0,0 +1,89 @@
+package secret
+
+import (
+	"unicode/utf8"
+)
+
+var (
+	// zeroWidthRunes are invisible characters which can be inserted into secrets to evade detection
+	zeroWidthRunes = map[rune]struct{}{
+		'\u00ad': {}, // soft hyphen
+		'\u200b': {}, // zero width space
+		'\u200c': {}, // zero width non-joiner
+		'\u200d': {}, // zero width joiner
+		'\u2060': {}, // word joiner
+		'\ufeff': {}, // zero width no-break space
+	}
+
+	// homoglyphs maps characters which look like ASCII letters to the ASCII letters
+	homoglyphs = map[rune]rune{
+		'а': 'a', 'с': 'c', 'е': 'e', 'і': 'i', 'ј': 'j', 'о': 'o', 'р': 'p', 'ѕ': 's', 'х': 'x', 'у': 'y',
+		'А': 'A', 'В': 'B', 'С': 'C', 'Е': 'E', 'Н': 'H', 'І': 'I', 'Ј': 'J', 'К': 'K', 'М': 'M', 'О': 'O',
+		'Р': 'P', 'Ѕ': 'S', 'Т': 'T', 'Х': 'X',
+	}
+)
+
+// normalizer strips zero-width characters and replaces homoglyphs with ASCII letters
+// so that obfuscated secrets can be detected. It keeps track of offsets in the original content
+// so that locations can be mapped back.
+type normalizer struct {
+	content []byte
+
+	// starts and ends hold the original offsets of the rune which produced each normalized byte
+	starts []int
+	ends   []int
+}
+
+func newNormalizer(content []byte) *normalizer {
+	if !needsNormalization(content) {
+		return &normalizer{content: content}
+	}
+
+	n := &normalizer{
+		content: make([]byte, 0, len(content)),
+		starts:  make([]int, 0, len(content)),
+		ends:    make([]int, 0, len(content)),
+	}
+	for i := 0; i < len(content); {
+		r, size := utf8.DecodeRune(content[i:])
+		if _, ok := zeroWidthRunes[r]; ok {
+			i += size
+			continue
+		}
+
+		b := content[i : i+size]
+		if ascii, ok := homoglyphs[r]; ok {
+			b = []byte{byte(ascii)}
+		}
+		for range b {
+			n.starts = append(n.starts, i)
+			n.ends = append(n.ends, i+size)
+		}
+		n.content = append(n.content, b...)
+		i += size
+	}
+	return n
+}
+
+func needsNormalization(content []byte) bool {
+	for _, r := range string(content) {
+		if _, ok := zeroWidthRunes[r]; ok {
+			return true
+		}
+		if _, ok := homoglyphs[r]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// original maps the location in the normalized content to the location in the original content
+func (n *normalizer) original(loc Location) Location {
+	if n.starts == nil || loc.Start == loc.End {
+		return loc
+	}
+	return Location{
+		Start: n.starts[loc.Start],
+		End:   n.ends[loc.End-1],
+	}
+}
@@ -0,0 +1,41 @@
+package secret
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/log"
+)
+
+var (
+	// e.g. <key>APIKey</key> <string>secret</string>
+	plistKeyValueRegex = regexp.MustCompile(`<key>\s*(?P<key>[^<]+?)\s*</key>\s*<string>(?P<value>[^<]*)</string>`)
+
+	binaryPlistMagic = []byte("bplist")
+)
+
+// detectPlist inspects the <string> values of credential-bearing <key>s in XML property lists.
+// Binary property lists are not decoded and skipped. The key is reported as the context.
+func (d secretKeyDetector) detectPlist(content []byte, path string) []Finding {
+	if bytes.HasPrefix(content, binaryPlistMagic) {
+		log.Logger.Debugf("Binary property list is not inspected for credential-bearing keys: %s", path)
+		return nil
+	}
+
+	var findings []Finding
+	keyIndex, valueIndex := plistKeyValueRegex.SubexpIndex("key"), plistKeyValueRegex.SubexpIndex("value")
+	for _, loc := range plistKeyValueRegex.FindAllSubmatchIndex(content, -1) {
+		key := string(content[loc[2*keyIndex]:loc[2*keyIndex+1]])
+		start, end := loc[2*valueIndex], loc[2*valueIndex+1]
+		value := string(content[start:end])
+		// Skip values with entities as they are not written as is
+		if !secretKey(d.patterns, key) || !sensitiveValue(key, value) || strings.Contains(value, "&") {
+			continue
+		}
+		finding := sensitiveKeyFinding(start, end)
+		finding.Context = key
+		findings = append(findings, finding)
+	}
+	return findings
+}
@@ -0,0 +1,63 @@
+package secret
+
+import (
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// Remediation priorities from the most urgent
+const (
+	PriorityP0 = "P0"
+	PriorityP1 = "P1"
+	PriorityP2 = "P2"
+	PriorityP3 = "P3"
+)
+
+// prioritySeverityScores are the base scores of severities. Severities not in the map score zero.
+var prioritySeverityScores = map[string]int{
+	"CRITICAL": 4,
+	"HIGH":     3,
+	"MEDIUM":   2,
+	"LOW":      1,
+}
+
+// DefaultPriority adds up the scores of the severity, the verification status, the confidence and the exposure of the finding
+// into the remediation priority, e.g. a live CRITICAL secret is P0 and a LOW secret is P3.
+func DefaultPriority(finding types.SecretFinding) string {
+	score := prioritySeverityScores[finding.Severity]
+	switch finding.Verification {
+	case VerificationLive:
+		score += 2
+	case VerificationRevoked:
+		score -= 2
+	}
+	if finding.Confidence == ConfidenceLow {
+		score--
+	}
+	if len(finding.Exposure) > 0 {
+		score++
+	}
+
+	switch {
+	case score >= 5:
+		return PriorityP0
+	case score >= 3:
+		return PriorityP1
+	case score == 2:
+		return PriorityP2
+	}
+	return PriorityP3
+}
+
+// prioritize sets the remediation priority of the findings if ReportPriority is enabled
+func (g Global) prioritize(findings []types.SecretFinding) {
+	if !g.ReportPriority {
+		return
+	}
+	priority := DefaultPriority
+	if g.PriorityFunc != nil {
+		priority = g.PriorityFunc
+	}
+	for i := range findings {
+		findings[i].Priority = priority(findings[i])
+	}
+}
@@ -0,0 +1,154 @@
+package secret_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/secret"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func TestDefaultPriority(t *testing.T) {
+	tests := []struct {
+		name    string
+		finding types.SecretFinding
+		want    string
+	}{
+		{
+			name: "live critical",
+			finding: types.SecretFinding{
+				Severity:     "CRITICAL",
+				Verification: secret.VerificationLive,
+			},
+			want: secret.PriorityP0,
+		},
+		{
+			name: "exposed critical",
+			finding: types.SecretFinding{
+				Severity: "CRITICAL",
+				Exposure: []string{"world-readable"},
+			},
+			want: secret.PriorityP0,
+		},
+		{
+			name:    "critical",
+			finding: types.SecretFinding{Severity: "CRITICAL"},
+			want:    secret.PriorityP1,
+		},
+		{
+			name: "revoked critical",
+			finding: types.SecretFinding{
+				Severity:     "CRITICAL",
+				Verification: secret.VerificationRevoked,
+			},
+			want: secret.PriorityP2,
+		},
+		{
+			name: "low confidence high",
+			finding: types.SecretFinding{
+				Severity:   "HIGH",
+				Confidence: secret.ConfidenceLow,
+			},
+			want: secret.PriorityP2,
+		},
+		{
+			name:    "medium",
+			finding: types.SecretFinding{Severity: "MEDIUM"},
+			want:    secret.PriorityP2,
+		},
+		{
+			name:    "low",
+			finding: types.SecretFinding{Severity: "LOW"},
+			want:    secret.PriorityP3,
+		},
+		{
+			name:    "unknown",
+			finding: types.SecretFinding{Severity: "UNKNOWN"},
+			want:    secret.PriorityP3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, secret.DefaultPriority(tt.finding))
+		})
+	}
+}
+
+func TestScanner_ReportPriority(t *testing.T) {
+	content := []byte("api_token=crit_0123456789abcdef\n# legacy low_0123456789abcdef\n")
+	rules := []secret.Rule{
+		{
+			ID:       "critical-token",
+			Category: "general",
+			Title:    "Critical token",
+			Severity: "CRITICAL",
+			Regex:    secret.MustCompile(`crit_\w+`),
+		},
+		{
+			ID:       "low-token",
+			Category: "general",
+			Title:    "Low token",
+			Severity: "LOW",
+			Regex:    secret.MustCompile(`low_\w+`),
+		},
+	}
+
+	tests := []struct {
+		name           string
+		reportPriority bool
+		priorityFunc   func(finding types.SecretFinding) string
+		want           map[string]string
+	}{
+		{
+			name:           "default priority",
+			reportPriority: true,
+			want: map[string]string{
+				"critical-token": secret.PriorityP0,
+				"low-token":      secret.PriorityP3,
+			},
+		},
+		{
+			name:           "custom priority",
+			reportPriority: true,
+			priorityFunc: func(finding types.SecretFinding) string {
+				if finding.RuleID == "low-token" {
+					return secret.PriorityP1
+				}
+				return secret.DefaultPriority(finding)
+			},
+			want: map[string]string{
+				"critical-token": secret.PriorityP0,
+				"low-token":      secret.PriorityP1,
+			},
+		},
+		{
+			name: "disabled",
+			want: map[string]string{
+				"critical-token": "",
+				"low-token":      "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := secret.NewScanner(&secret.Config{
+				CustomRules:          rules,
+				CommentSeverityDelta: 1,
+				Verifiers:            map[string]secret.Verifier{"critical-token": &mockVerifier{}},
+				ReportPriority:       tt.reportPriority,
+				PriorityFunc:         tt.priorityFunc,
+			})
+			got := s.Scan(secret.ScanArgs{
+				FilePath: "config.txt",
+				Content:  content,
+			})
+
+			priorities := map[string]string{}
+			for _, f := range got.Findings {
+				priorities[f.RuleID] = f.Priority
+			}
+			assert.Equal(t, tt.want, priorities)
+		})
+	}
+}
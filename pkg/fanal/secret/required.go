@@ -0,0 +1,135 @@
+package secret
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	dio "github.com/aquasecurity/go-dep-parser/pkg/io"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/utils"
+)
+
+var (
+	skipFiles = []string{
+		"go.mod",
+		"go.sum",
+		"package-lock.json",
+		"yarn.lock",
+		"pnpm-lock.yaml",
+		"Pipfile.lock",
+		"Gemfile.lock",
+	}
+	skipDirs = []string{".git", "node_modules"}
+	skipExts = []string{
+		".jpg", ".png", ".gif", ".doc", ".pdf", ".bin", ".svg", ".socket", ".deb", ".rpm",
+		".zip", ".gz", ".gzip", ".tar", ".pyc",
+	}
+)
+
+// SkipReason represents why a file is not scanned
+type SkipReason string
+
+const (
+	SkipReasonSmallFile   SkipReason = "small-file"
+	SkipReasonDir         SkipReason = "skip-dir"
+	SkipReasonFile        SkipReason = "skip-file"
+	SkipReasonExt         SkipReason = "skip-extension"
+	SkipReasonAllowedPath SkipReason = "allowed-path"
+	SkipReasonBinary      SkipReason = "binary"
+	SkipReasonFailFast    SkipReason = "fail-fast"
+	SkipReasonLargeFile   SkipReason = "large-file"
+	SkipReasonUnlistedExt SkipReason = "unlisted-ext"
+	SkipReasonDepth       SkipReason = "max-depth"
+	SkipReasonByteBudget  SkipReason = "byte-budget"
+	SkipReasonUnreadable  SkipReason = "unreadable"
+)
+
+// maxFileSize limits the size of files read into memory by ScanDir and ScanTar
+const maxFileSize = 100 << 20
+
+// Required checks if the file should be scanned for secrets
+func (s *Scanner) Required(filePath string, fi os.FileInfo) bool {
+	return s.skipReason(filePath, fi) == ""
+}
+
+// IsBinary checks if the content is binary according to BinaryThreshold
+func (g Global) IsBinary(content dio.ReadSeekerAt, fileSize int64) (bool, error) {
+	return utils.IsBinaryWithThreshold(content, fileSize, g.BinaryThreshold)
+}
+
+// skipReason returns the reason why the file should not be scanned.
+// It returns an empty string if the file should be scanned.
+func (s *Scanner) skipReason(filePath string, fi os.FileInfo) SkipReason {
+	// Skip small files
+	if fi.Size() < 10 {
+		return SkipReasonSmallFile
+	}
+
+	filePath = s.normalizeSeparators(filePath)
+	dir, fileName := filepath.Split(filePath)
+	dir = filepath.ToSlash(dir)
+	dirs := strings.Split(dir, "/")
+
+	// Check if the directory should be skipped.
+	// .git/config is an exception as it may have credentials in remote URLs.
+	gitConfig := s.ScanGitConfig && fileName == "config" && len(dirs) > 1 && dirs[len(dirs)-2] == ".git"
+	for _, skipDir := range skipDirs {
+		if skipDir == ".git" && gitConfig {
+			continue
+		}
+		if slices.Contains(dirs, skipDir) {
+			return SkipReasonDir
+		}
+	}
+
+	// Check if the file should be skipped
+	if slices.Contains(skipFiles, fileName) {
+		return SkipReasonFile
+	}
+
+	// Check if the file extension should be skipped
+	ext := filepath.Ext(fileName)
+	if slices.Contains(skipExts, ext) && !(s.ScanCompressed && slices.Contains(gzipExts, ext)) {
+		return SkipReasonExt
+	}
+	if len(s.ScanExts) > 0 && !slices.Contains(s.ScanExts, ext) {
+		return SkipReasonUnlistedExt
+	}
+
+	if s.MaxDepth > 0 && depth(filePath) > s.MaxDepth {
+		return SkipReasonDepth
+	}
+
+	if s.AllowPath(filePath) {
+		return SkipReasonAllowedPath
+	}
+
+	return ""
+}
+
+// skipDirReason returns the reason why no file under the directory should be scanned.
+// It returns an empty string if the directory should be walked.
+func (s *Scanner) skipDirReason(dirPath string) SkipReason {
+	// .git is walked for .git/config
+	name := filepath.Base(dirPath)
+	if slices.Contains(skipDirs, name) && !(s.ScanGitConfig && name == ".git") {
+		return SkipReasonDir
+	}
+
+	// Files in the directory are deeper than the directory itself
+	if s.MaxDepth > 0 && depth(dirPath) >= s.MaxDepth {
+		return SkipReasonDepth
+	}
+	return ""
+}
+
+// depth returns the number of path elements of the file relative to the scan root.
+// Files directly under the root have depth 1.
+func depth(filePath string) int {
+	filePath = strings.Trim(path.Clean(filepath.ToSlash(filePath)), "/")
+	return strings.Count(filePath, "/") + 1
+}
@@ -0,0 +1,51 @@
+package secret
+
+import (
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// RiskWeights maps severities to the weights used to compute risk scores.
+// Severities not in the map have no weight.
+type RiskWeights map[string]int
+
+// DefaultRiskWeights are used by RiskScore and FileRiskScores
+var DefaultRiskWeights = RiskWeights{
+	"CRITICAL": 10,
+	"HIGH":     5,
+	"MEDIUM":   2,
+	"LOW":      1,
+}
+
+// RiskScore returns the severity-weighted sum of all the findings with DefaultRiskWeights
+func RiskScore(secrets []types.Secret) int {
+	return DefaultRiskWeights.Score(secrets)
+}
+
+// FileRiskScores returns the severity-weighted sum of the findings per file path with DefaultRiskWeights
+func FileRiskScores(secrets []types.Secret) map[string]int {
+	return DefaultRiskWeights.FileScores(secrets)
+}
+
+// Score returns the severity-weighted sum of all the findings
+func (w RiskWeights) Score(secrets []types.Secret) int {
+	var score int
+	for _, s := range secrets {
+		for _, finding := range s.Findings {
+			score += w[finding.Severity]
+		}
+	}
+	return score
+}
+
+// FileScores returns the severity-weighted sum of the findings per file path.
+// Results for the same file (e.g. in different layers) are added up.
+func (w RiskWeights) FileScores(secrets []types.Secret) map[string]int {
+	scores := map[string]int{}
+	for _, s := range secrets {
+		if len(s.Findings) == 0 {
+			continue
+		}
+		scores[s.FilePath] += w.Score([]types.Secret{s})
+	}
+	return scores
+}
@@ -0,0 +1,101 @@
+package secret_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/secret"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func TestRiskScore(t *testing.T) {
+	secrets := []types.Secret{
+		{
+			FilePath: "config.yaml",
+			Findings: []types.SecretFinding{
+				{
+					RuleID:   "aws-access-key-id",
+					Severity: "CRITICAL",
+				},
+				{
+					RuleID:   "aws-account-id",
+					Severity: "HIGH",
+				},
+			},
+		},
+		{
+			FilePath: "deploy.sh",
+			Findings: []types.SecretFinding{
+				{
+					RuleID:   "slack-web-hook",
+					Severity: "MEDIUM",
+				},
+				{
+					RuleID:   "twitch-api-token",
+					Severity: "LOW",
+				},
+				{
+					RuleID:   "custom",
+					Severity: "UNKNOWN",
+				},
+			},
+		},
+		{
+			FilePath: "config.yaml",
+			Findings: []types.SecretFinding{
+				{
+					RuleID:   "github-pat",
+					Severity: "CRITICAL",
+				},
+			},
+		},
+		{
+			FilePath: "clean.txt",
+		},
+	}
+
+	tests := []struct {
+		name           string
+		weights        secret.RiskWeights
+		want           int
+		wantFileScores map[string]int
+	}{
+		{
+			name:    "default weights",
+			weights: secret.DefaultRiskWeights,
+			want:    28,
+			wantFileScores: map[string]int{
+				"config.yaml": 25,
+				"deploy.sh":   3,
+			},
+		},
+		{
+			name: "custom weights",
+			weights: secret.RiskWeights{
+				"CRITICAL": 100,
+				"MEDIUM":   1,
+				"UNKNOWN":  1,
+			},
+			want: 202,
+			wantFileScores: map[string]int{
+				"config.yaml": 200,
+				"deploy.sh":   2,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.weights.Score(secrets))
+			assert.Equal(t, tt.wantFileScores, tt.weights.FileScores(secrets))
+		})
+	}
+
+	t.Run("package functions", func(t *testing.T) {
+		assert.Equal(t, 28, secret.RiskScore(secrets))
+		assert.Equal(t, map[string]int{
+			"config.yaml": 25,
+			"deploy.sh":   3,
+		}, secret.FileRiskScores(secrets))
+	})
+}
@@ -2,18 +2,24 @@ package secret
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"io"
 	"os"
 	"regexp"
 	"sort"
 	"strings"
 	"sync"
+	"time"
+	"unicode/utf8"
 
+	"github.com/bmatcuk/doublestar"
 	"github.com/samber/lo"
 	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 	"gopkg.in/yaml.v3"
 
+	"github.com/aquasecurity/trivy/pkg/clock"
 	"github.com/aquasecurity/trivy/pkg/fanal/log"
 	"github.com/aquasecurity/trivy/pkg/fanal/types"
 )
@@ -38,22 +44,250 @@ type Config struct {
 	CustomRules      []Rule       `yaml:"rules"`
 	CustomAllowRules AllowRules   `yaml:"allow-rules"`
 	ExcludeBlock     ExcludeBlock `yaml:"exclude-block"`
+
+	// Lower the severity of secrets found in comments by the specified number of levels.
+	CommentSeverityDelta int `yaml:"comment-severity-delta"`
+
+	// Line comment prefixes in addition to the default prefixes "//" and "#", e.g. "--" for SQL
+	CommentPrefixes []string `yaml:"comment-prefixes"`
+
+	// Count findings in comments and in code across scans, which are returned by Scanner.CommentStats
+	ReportCommentStats bool `yaml:"report-comment-stats"`
+
+	// Strip zero-width characters and replace homoglyphs before matching.
+	NormalizeUnicode bool `yaml:"normalize-unicode"`
+
+	// Join string literals concatenated on the same line before matching, e.g. "abc" + "def".
+	CollapseConcatenation bool `yaml:"collapse-concatenation"`
+
+	// Stop the scan session as soon as a secret matching any of the rules is found.
+	FailFastOn []FailFastRule `yaml:"fail-fast-on"`
+
+	// Ignore secrets containing or followed by any of the markers. The default markers are used if not specified.
+	TruncationMarkers []string `yaml:"truncation-markers"`
+
+	// Transform file paths before allow path checks and findings are emitted,
+	// e.g. to strip a temporary extraction directory. It is only available programmatically.
+	PathTransform func(string) string `yaml:"-"`
+
+	// Replace backslashes in file paths with forward slashes before PathTransform,
+	// so that Windows paths are matched by allow rules and reported consistently.
+	NormalizePathSeparators bool `yaml:"normalize-path-separators"`
+
+	// Report secrets with the severity or higher even if their paths are allowed by allow rules.
+	AllowListSeverityCeiling string `yaml:"allow-list-severity-ceiling"`
+
+	// Decrypt the content before scanning, e.g. files encrypted by SOPS. If the hook returns true,
+	// the returned plaintext is scanned instead of the content. It is only available programmatically.
+	DecryptHook func(path string, content []byte) ([]byte, bool) `yaml:"-"`
+
+	// Add a fingerprint to each finding so that it can be identified across scans.
+	Fingerprints bool `yaml:"fingerprints"`
+
+	// Ignore secrets whose fingerprints are in the set, e.g. findings already known from a previous scan.
+	// It is only available programmatically.
+	IgnoreFingerprints map[string]struct{} `yaml:"-"`
+
+	// Drop findings for which the function returns true, e.g. by querying an internal allow-list service.
+	// It runs after the built-in and configured allow rules. It is only available programmatically.
+	AllowFunc func(finding types.SecretFinding) bool `yaml:"-"`
+
+	// Report the remediation priority of each finding from P0 to P3, computed by PriorityFunc.
+	// DefaultPriority is used if PriorityFunc is nil, which is only available programmatically.
+	ReportPriority bool                                     `yaml:"report-priority"`
+	PriorityFunc   func(finding types.SecretFinding) string `yaml:"-"`
+
+	// Decompress gzip-compressed files before scanning
+	ScanCompressed bool `yaml:"scan-compressed"`
+
+	// Report the region matched by the regex in Match instead of the whole line, and the secret in Secret
+	MatchRegion bool `yaml:"match-region"`
+
+	// Scan .git/config for credentials in remote URLs although .git directories are skipped.
+	// It takes effect only in Scanner.ScanDir and Scanner.ScanTar as the filesystem walker never enters .git directories.
+	ScanGitConfig bool `yaml:"scan-git-config"`
+
+	// Populate the author of the line containing each secret by git blame when scanning git repositories
+	AttributeAuthor bool `yaml:"attribute-author"`
+
+	// Report the nearest function declaration before each secret in the finding context, e.g. "func connect"
+	ReportEnclosingFunction bool `yaml:"report-enclosing-function"`
+
+	// Fraction of non-text bytes in the head of the file above which the file is regarded as binary and not scanned.
+	// A single non-text byte makes the file binary if not specified.
+	BinaryThreshold float64 `yaml:"binary-threshold"`
+
+	// Enable the generic high-entropy detector reporting string values whose Shannon entropy is the threshold or higher.
+	// It doesn't affect the entropy of regex rules.
+	GenericEntropyThreshold float64 `yaml:"generic-entropy-threshold"`
+
+	// Report whether secrets are exposed by their files, i.e. world-readable files and files under public web directories.
+	// Permissions are checked only if the file info is available, e.g. not for container images.
+	ReportExposure bool `yaml:"report-exposure"`
+
+	// Report the language of the file of each finding by its extension, e.g. "go", "python" and "yaml"
+	ReportLanguage bool `yaml:"report-language"`
+
+	// Report up to the number of lines above and below each finding as LinesBefore and LinesAfter, e.g. for custom rendering.
+	// The lines are censored. It doesn't affect Code.
+	ContextLines int `yaml:"context-lines"`
+
+	// Scan template files such as .env.example although example paths are allowed by default.
+	// Placeholders in them are ignored and the severity of the other secrets is lowered by one level.
+	ScanTemplates bool `yaml:"scan-templates"`
+
+	// Report files matching any of the globs even if no secrets are found, marked as clean with the config hash
+	EmitCleanFiles []string `yaml:"emit-clean-files"`
+
+	// Skip files deeper than the depth relative to the scan root. Files directly under the root have depth 1.
+	// All files are scanned if not specified.
+	MaxDepth int `yaml:"max-depth"`
+
+	// Scan only files with any of the extensions, e.g. ".env". All extensions are scanned if not specified.
+	ScanExts []string `yaml:"scan-exts"`
+
+	// Detect values of credential-bearing keys in structured files such as .env, JSON, YAML and INI files,
+	// e.g. DB_PASSWORD=..., even if no rule detects them
+	ScanSecretKeys bool `yaml:"scan-secret-keys"`
+
+	// Detect payment card numbers which have a known issuer prefix and pass the Luhn check.
+	// Disabled by default as order IDs and other numbers may pass the check as well.
+	ScanCreditCards bool `yaml:"scan-credit-cards"`
+
+	// Detect credentials passed to curl and wget in command lines, e.g. "curl -u user:pass" and "-H 'Authorization: Bearer ...'"
+	ScanCommandLines bool `yaml:"scan-command-lines"`
+
+	// Decode base64 data URIs such as "data:application/json;base64,..." and scan their payloads
+	ScanDataURIs bool `yaml:"scan-data-uris"`
+
+	// Report only one of the findings of different rules detecting the same secret,
+	// i.e. the finding of the rule with the highest priority, severity or the smallest ID
+	DeduplicateFindings bool `yaml:"deduplicate-findings"`
+
+	// Report only one of the findings of different rules and detectors at exactly the same location,
+	// e.g. a generic rule and a specific rule matching the same secret. The winner is picked as in DeduplicateFindings.
+	DeduplicateSpans bool `yaml:"deduplicate-spans"`
+
+	// Trim leading and trailing whitespace captured in secrets, e.g. by a greedy secret group
+	TrimSecrets bool `yaml:"trim-secrets"`
+
+	// Trim leading and trailing quotes captured in secrets
+	TrimQuotes bool `yaml:"trim-quotes"`
+
+	// Number findings sequentially in each file in the output order, starting from 1
+	FindingIndex bool `yaml:"finding-index"`
+
+	// Regex patterns of credential-bearing key names used by ScanSecretKeys and ScanKV, e.g. "_credential$".
+	// They are matched case-insensitively. The default patterns are used if not specified.
+	SecretKeyPatterns []string `yaml:"secret-key-patterns"`
+
+	// Verify secrets with the verifiers keyed by rule ID. It is only available programmatically.
+	Verifiers map[string]Verifier `yaml:"-"`
+
+	// Maximum number of verification calls per second. Unlimited if not specified.
+	VerifyRateLimit float64 `yaml:"verify-rate-limit"`
+
+	// Maximum number of concurrent verification calls
+	VerifyConcurrency int `yaml:"verify-concurrency"`
+
+	// Initial backoff when verification calls are rate limited by the provider
+	VerifyBackoff time.Duration `yaml:"verify-backoff"`
+
+	// Override the severity of verified secrets by the verification status, e.g. "live: CRITICAL"
+	VerificationSeverityMap map[types.SecretVerificationStatus]string `yaml:"verification-severity-map"`
+
+	// Write findings as JSON lines as soon as they are found by sessions, e.g. ScanDir, for very long scans.
+	// It is only available programmatically.
+	ResultSink io.Writer `yaml:"-"`
 }
 
 type Global struct {
-	Rules        []Rule
-	AllowRules   AllowRules
-	ExcludeBlock ExcludeBlock
+	Rules                    []Rule
+	AllowRules               AllowRules
+	ExcludeBlock             ExcludeBlock
+	Detectors                []Detector
+	CommentSeverityDelta     int
+	CommentPrefixes          []string
+	NormalizeUnicode         bool
+	CollapseConcatenation    bool
+	FailFastOn               []FailFastRule
+	TruncationMarkers        []string
+	PathTransform            func(string) string
+	NormalizePathSeparators  bool
+	AllowListSeverityCeiling string
+	DecryptHook              func(path string, content []byte) ([]byte, bool)
+	Fingerprints             bool
+	IgnoreFingerprints       map[string]struct{}
+	AllowFunc                func(finding types.SecretFinding) bool
+	ReportPriority           bool
+	PriorityFunc             func(finding types.SecretFinding) string
+	ScanCompressed           bool
+	MatchRegion              bool
+	ScanGitConfig            bool
+	AttributeAuthor          bool
+	ReportEnclosingFunction  bool
+	BinaryThreshold          float64
+	SecretKeyPatterns        []*regexp.Regexp
+	FindingIndex             bool
+	DeduplicateFindings      bool
+	DeduplicateSpans         bool
+	TrimSecrets              bool
+	TrimQuotes               bool
+	ReportExposure           bool
+	ReportLanguage           bool
+	ContextLines             int
+	ScanTemplates            bool
+	EmitCleanFiles           []string
+	ScanExts                 []string
+	ScanDataURIs             bool
+	MaxDepth                 int
+
+	truncationMatchers []truncationMatcher
+	allowUsage         *allowUsage
+	expiredAllowRules  []AllowRule
+	commentStats       *commentStats
+	verification       *verification
+	sink               *sink
+	configHash         string
 }
 
 // Allow checks if the match is allowed
 func (g Global) Allow(match string) bool {
-	return g.AllowRules.Allow(match)
+	return g.used(g.AllowRules.regexRule("", match))
 }
 
 // AllowPath checks if the path is allowed
 func (g Global) AllowPath(path string) bool {
-	return g.AllowRules.AllowPath(path)
+	// Allowed paths still need to be scanned for secrets above the ceiling
+	if g.AllowListSeverityCeiling != "" {
+		return false
+	}
+	return g.allowPath(g.transformPath(path))
+}
+
+func (g Global) allowPath(path string) bool {
+	return g.used(g.pathRule("", path))
+}
+
+// aboveCeiling checks if secrets with the severity must be reported regardless of allowed paths
+func (g Global) aboveCeiling(severity string) bool {
+	return g.AllowListSeverityCeiling != "" && severityIndex(severity) >= severityIndex(g.AllowListSeverityCeiling)
+}
+
+func (g Global) transformPath(path string) string {
+	path = g.normalizeSeparators(path)
+	if g.PathTransform == nil {
+		return path
+	}
+	return g.PathTransform(path)
+}
+
+// normalizeSeparators replaces backslashes with forward slashes if NormalizePathSeparators is enabled
+func (g Global) normalizeSeparators(path string) string {
+	if !g.NormalizePathSeparators {
+		return path
+	}
+	return strings.ReplaceAll(path, `\`, "/")
 }
 
 // Regexp adds unmarshalling from YAML for regexp.Regexp
@@ -80,6 +314,11 @@ func (r *Regexp) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
+// MarshalYAML marshals regexp.Regexp into YAML
+func (r *Regexp) MarshalYAML() (interface{}, error) {
+	return r.String(), nil
+}
+
 type Rule struct {
 	ID              string                   `yaml:"id"`
 	Category        types.SecretRuleCategory `yaml:"category"`
@@ -91,18 +330,47 @@ type Rule struct {
 	AllowRules      AllowRules               `yaml:"allow-rules"`
 	ExcludeBlock    ExcludeBlock             `yaml:"exclude-block"`
 	SecretGroupName string                   `yaml:"secret-group-name"`
+
+	// Detect only secrets after the first occurrence of the delimiter on each line, e.g. "=" to ignore keys
+	AfterDelimiter string `yaml:"after-delimiter"`
+
+	// Minimum Shannon entropy of secrets in bits per character. Secrets with lower entropy are ignored.
+	Entropy float64 `yaml:"entropy"`
+
+	// Report secrets only if they are syntactically valid values of the type, i.e. "jwt", "base64", "hex" or "uuid"
+	ValidateAs string `yaml:"validate-as"`
+
+	// Suppress matches of the same secret within the number of lines after a reported match, e.g. repeated tokens in logs
+	SuppressWithinLines int `yaml:"suppress-within-lines"`
+
+	// Report secrets only if they are verified live by the verifier of the rule, e.g. for noisy patterns.
+	// Secrets are reported with low confidence if the rule has no verifier.
+	RequireVerification bool `yaml:"require-verification"`
+
+	// Rules with higher priorities win when DeduplicateFindings picks one of overlapping findings.
+	// The severity and the ID break ties.
+	Priority int `yaml:"priority"`
 }
 
 func (s *Scanner) FindLocations(r Rule, content []byte) []Location {
+	return matchLocations(s.findRuleMatches(r, content))
+}
+
+func (s *Scanner) FindSubmatchLocations(r Rule, content []byte) []Location {
+	return matchLocations(s.findSubmatches(r, content))
+}
+
+// findRuleMatches returns secrets detected by the rule along with the regions matched by the regex
+func (s *Scanner) findRuleMatches(r Rule, content []byte) []Match {
 	if r.Regex == nil {
 		return nil
 	}
 
 	if r.SecretGroupName != "" {
-		return s.FindSubmatchLocations(r, content)
+		return s.findSubmatches(r, content)
 	}
 
-	var locs []Location
+	var matches []Match
 	indices := r.Regex.FindAllIndex(content, -1)
 	for _, index := range indices {
 		loc := Location{
@@ -110,17 +378,21 @@ func (s *Scanner) FindLocations(r Rule, content []byte) []Location {
 			End:   index[1],
 		}
 
-		if s.AllowLocation(r, content, loc) {
+		if s.AllowLocation(r, content, loc) || s.AllowSecret(r, content, loc) {
 			continue
 		}
 
-		locs = append(locs, loc)
+		matches = append(matches, Match{
+			Rule:     r,
+			Location: loc,
+			Region:   loc,
+		})
 	}
-	return locs
+	return matches
 }
 
-func (s *Scanner) FindSubmatchLocations(r Rule, content []byte) []Location {
-	var submatchLocations []Location
+func (s *Scanner) findSubmatches(r Rule, content []byte) []Match {
+	var submatches []Match
 	matchsIndices := r.Regex.FindAllSubmatchIndex(content, -1)
 	for _, matchIndices := range matchsIndices {
 		matchLocation := Location{ // first two indexes are always start and end of the whole match
@@ -132,17 +404,41 @@ func (s *Scanner) FindSubmatchLocations(r Rule, content []byte) []Location {
 			continue
 		}
 
-		matchSubgroupsLocations := r.getMatchSubgroupsLocations(matchIndices)
-		if len(matchSubgroupsLocations) > 0 {
-			submatchLocations = append(submatchLocations, matchSubgroupsLocations...)
+		key := r.matchKey(content, matchIndices)
+		for _, loc := range r.getMatchSubgroupsLocations(matchIndices) {
+			// Skip the secret if it is the key itself, e.g. token = "token"
+			if s.AllowSecret(r, content, loc) || valueIsKey(key, string(content[loc.Start:loc.End])) {
+				continue
+			}
+			submatches = append(submatches, Match{
+				Rule:     r,
+				Location: loc,
+				Region:   matchLocation,
+			})
 		}
 	}
-	return submatchLocations
+	return submatches
+}
+
+func matchLocations(matches []Match) []Location {
+	return lo.Map(matches, func(m Match, _ int) Location {
+		return m.Location
+	})
 }
 
 func (s *Scanner) AllowLocation(r Rule, content []byte, loc Location) bool {
 	match := string(content[loc.Start:loc.End])
-	return s.Allow(match) || r.Allow(match)
+	return s.used(s.AllowRules.regexRule(r.ID, match)) || s.used(r.AllowRules.regexRule(r.ID, match))
+}
+
+// AllowSecret checks if the secret value is allowed
+func (s *Scanner) AllowSecret(r Rule, content []byte, loc Location) bool {
+	if s.truncated(content, loc) {
+		return true
+	}
+	secret := string(content[loc.Start:loc.End])
+	return s.used(s.AllowRules.prefixRule(r.ID, secret)) || s.used(r.AllowRules.prefixRule(r.ID, secret)) ||
+		s.used(s.AllowRules.lengthRule(r.ID, secret)) || s.used(r.AllowRules.lengthRule(r.ID, secret))
 }
 
 func (r *Rule) getMatchSubgroupsLocations(matchLocs []int) []Location {
@@ -157,10 +453,37 @@ func (r *Rule) getMatchSubgroupsLocations(matchLocs []int) []Location {
 	return locations
 }
 
+// matchKey returns the key matched by the "key" group of the regex, e.g. the variable name the secret is assigned to.
+// It returns an empty string if the regex has no such group or it didn't participate in the match.
+func (r *Rule) matchKey(content []byte, matchLocs []int) string {
+	i := r.Regex.SubexpIndex("key")
+	if i < 0 || matchLocs[2*i] < 0 {
+		return ""
+	}
+	return string(content[matchLocs[2*i]:matchLocs[2*i+1]])
+}
+
 func (r *Rule) MatchPath(path string) bool {
 	return r.Path == nil || r.Path.MatchString(path)
 }
 
+// MatchAfterDelimiter checks if the location starts after the first delimiter on the line.
+// It always returns true if AfterDelimiter is not specified.
+func (r *Rule) MatchAfterDelimiter(content []byte, loc Location) bool {
+	if r.AfterDelimiter == "" {
+		return true
+	}
+	lineStart := bytes.LastIndex(content[:loc.Start], lineSep) + 1
+	lineEnd := bytes.Index(content[lineStart:], lineSep)
+	if lineEnd == -1 {
+		lineEnd = len(content)
+	} else {
+		lineEnd += lineStart
+	}
+	i := bytes.Index(content[lineStart:lineEnd], []byte(r.AfterDelimiter))
+	return i != -1 && loc.Start >= lineStart+i+len(r.AfterDelimiter)
+}
+
 func (r *Rule) MatchKeywords(content []byte) bool {
 	if len(r.Keywords) == 0 {
 		return true
@@ -184,30 +507,114 @@ func (r *Rule) Allow(match string) bool {
 }
 
 type AllowRule struct {
-	ID          string  `yaml:"id"`
-	Description string  `yaml:"description"`
-	Regex       *Regexp `yaml:"regex"`
-	Path        *Regexp `yaml:"path"`
+	ID            string   `yaml:"id"`
+	Description   string   `yaml:"description"`
+	Regex         *Regexp  `yaml:"regex"`
+	Path          *Regexp  `yaml:"path"`
+	PathGlob      string   `yaml:"path-glob"`
+	MatchPrefixes []string `yaml:"match-prefixes"`
+
+	// Allow secrets of exactly the length, or within the range of lengths, in characters, e.g. 32-char build IDs.
+	// MaxLength 0 means no upper bound.
+	Length    int `yaml:"length"`
+	MinLength int `yaml:"min-length"`
+	MaxLength int `yaml:"max-length"`
+
+	// Apply the allow rule only to secrets detected by the rule. It applies to all rules if empty.
+	RuleID string `yaml:"rule"`
+
+	// Ignore the allow rule from the time, e.g. 2024-06-30 for a temporary suppression during remediation.
+	// It never expires if zero.
+	Expires time.Time `yaml:"expires"`
+}
+
+// MatchPath checks if the path matches Path or PathGlob
+func (r AllowRule) MatchPath(path string) bool {
+	if r.Path != nil && r.Path.MatchString(path) {
+		return true
+	}
+	if r.PathGlob == "" {
+		return false
+	}
+	matched, err := doublestar.Match(r.PathGlob, strings.TrimPrefix(path, "/"))
+	return err == nil && matched
+}
+
+// MatchLength checks if the length of the secret is Length or within MinLength and MaxLength
+func (r AllowRule) MatchLength(secret string) bool {
+	n := utf8.RuneCountInString(secret)
+	if r.Length > 0 {
+		return n == r.Length
+	}
+	if r.MinLength == 0 && r.MaxLength == 0 {
+		return false
+	}
+	return n >= r.MinLength && (r.MaxLength == 0 || n <= r.MaxLength)
+}
+
+func (r AllowRule) appliesTo(ruleID string) bool {
+	return r.RuleID == "" || r.RuleID == ruleID
 }
 
 type AllowRules []AllowRule
 
 func (rules AllowRules) AllowPath(path string) bool {
-	for _, rule := range rules {
-		if rule.Path != nil && rule.Path.MatchString(path) {
-			return true
+	return rules.pathRule("", path) != nil
+}
+
+func (rules AllowRules) Allow(match string) bool {
+	return rules.regexRule("", match) != nil
+}
+
+// AllowPrefix checks if the secret starts with one of the allowed prefixes
+func (rules AllowRules) AllowPrefix(secret string) bool {
+	return rules.prefixRule("", secret) != nil
+}
+
+// pathRule returns the first allow rule applying to the rule ID and matching the path.
+// An empty rule ID matches only allow rules which apply to all rules.
+func (rules AllowRules) pathRule(ruleID, path string) *AllowRule {
+	for i, rule := range rules {
+		if rule.appliesTo(ruleID) && rule.MatchPath(path) {
+			return &rules[i]
 		}
 	}
-	return false
+	return nil
 }
 
-func (rules AllowRules) Allow(match string) bool {
-	for _, rule := range rules {
-		if rule.Regex != nil && rule.Regex.MatchString(match) {
-			return true
+// regexRule returns the first allow rule applying to the rule ID and matching the match
+func (rules AllowRules) regexRule(ruleID, match string) *AllowRule {
+	for i, rule := range rules {
+		if rule.appliesTo(ruleID) && rule.Regex != nil && rule.Regex.MatchString(match) {
+			return &rules[i]
 		}
 	}
-	return false
+	return nil
+}
+
+// prefixRule returns the first allow rule applying to the rule ID whose prefixes match the secret
+func (rules AllowRules) prefixRule(ruleID, secret string) *AllowRule {
+	for i, rule := range rules {
+		if !rule.appliesTo(ruleID) {
+			continue
+		}
+		for _, prefix := range rule.MatchPrefixes {
+			if strings.HasPrefix(secret, prefix) {
+				return &rules[i]
+			}
+		}
+	}
+	return nil
+}
+
+// lengthRule returns the first allow rule applying to the rule ID whose length range matches the secret
+func (rules AllowRules) lengthRule(ruleID, secret string) *AllowRule {
+	for i, rule := range rules {
+		if rule.appliesTo(ruleID) && rule.MatchLength(secret) {
+			return &rules[i]
+		}
+	}
+	return nil
 }
 
 type ExcludeBlock struct {
@@ -287,6 +694,12 @@ func ParseConfig(configPath string) (*Config, error) {
 		return nil, xerrors.Errorf("secrets config decode error: %w", err)
 	}
 
+	for _, rule := range config.CustomRules {
+		if _, ok := validators[rule.ValidateAs]; rule.ValidateAs != "" && !ok {
+			return nil, xerrors.Errorf("unknown validate-as %q in the rule %s", rule.ValidateAs, rule.ID)
+		}
+	}
+
 	return &config, nil
 }
 
@@ -294,8 +707,13 @@ func NewScanner(config *Config) Scanner {
 	// Use the default rules
 	if config == nil {
 		return Scanner{Global: &Global{
-			Rules:      builtinRules,
-			AllowRules: builtinAllowRules,
+			Rules:              builtinRules,
+			AllowRules:         builtinAllowRules,
+			Detectors:          slices.Clone(builtinDetectors),
+			TruncationMarkers:  defaultTruncationMarkers,
+			truncationMatchers: defaultTruncationMatchers,
+			SecretKeyPatterns:  defaultSecretKeyRegexes,
+			CommentPrefixes:    defaultCommentPrefixes,
 		}}
 	}
 
@@ -315,42 +733,348 @@ func NewScanner(config *Config) Scanner {
 		return !slices.Contains(config.DisableRuleIDs, v.ID)
 	})
 
+	// Use the custom key patterns in detectors for structured files
+	secretKeyPatterns := defaultSecretKeyRegexes
+	if config.SecretKeyPatterns != nil {
+		secretKeyPatterns = compileSecretKeyPatterns(config.SecretKeyPatterns)
+	}
+
+	enabledDetectors := builtinDetectors
+	if config.ScanSecretKeys {
+		enabledDetectors = append(slices.Clone(enabledDetectors), secretKeyDetector{patterns: secretKeyPatterns})
+	}
+	if config.ScanCreditCards {
+		enabledDetectors = append(slices.Clone(enabledDetectors), creditCardDetector{})
+	}
+	if config.ScanCommandLines {
+		enabledDetectors = append(slices.Clone(enabledDetectors), commandLineDetector{})
+	}
+	if config.GenericEntropyThreshold > 0 {
+		enabledDetectors = append(slices.Clone(enabledDetectors), genericEntropyDetector{threshold: config.GenericEntropyThreshold})
+	}
+	if len(config.EnableBuiltinRuleIDs) != 0 {
+		enabledDetectors = lo.Filter(enabledDetectors, func(v Detector, _ int) bool {
+			return slices.Contains(config.EnableBuiltinRuleIDs, v.Name())
+		})
+	}
+	detectors := lo.Filter(enabledDetectors, func(v Detector, _ int) bool {
+		return !slices.Contains(config.DisableRuleIDs, v.Name())
+	})
+
 	// Disable specified allow rules
 	allowRules := append(builtinAllowRules, config.CustomAllowRules...)
 	allowRules = lo.Filter(allowRules, func(v AllowRule, _ int) bool {
 		return !slices.Contains(config.DisableAllowRuleIDs, v.ID)
 	})
 
+	// Expired allow rules are treated as absent
+	now := clock.Now()
+	allowRules, expiredAllowRules := activeAllowRules(allowRules, now)
+	rules = lo.Map(rules, func(v Rule, _ int) Rule {
+		var expired []AllowRule
+		v.AllowRules, expired = activeAllowRules(v.AllowRules, now)
+		expiredAllowRules = append(expiredAllowRules, expired...)
+		return v
+	})
+
+	truncationMarkers := lo.Ternary(config.TruncationMarkers == nil, defaultTruncationMarkers, config.TruncationMarkers)
+
 	return Scanner{Global: &Global{
-		Rules:        rules,
-		AllowRules:   allowRules,
-		ExcludeBlock: config.ExcludeBlock,
+		Rules:                    rules,
+		AllowRules:               allowRules,
+		ExcludeBlock:             config.ExcludeBlock,
+		Detectors:                detectors,
+		CommentSeverityDelta:     config.CommentSeverityDelta,
+		CommentPrefixes:          lo.Uniq(append(slices.Clone(defaultCommentPrefixes), config.CommentPrefixes...)),
+		NormalizeUnicode:         config.NormalizeUnicode,
+		CollapseConcatenation:    config.CollapseConcatenation,
+		FailFastOn:               config.FailFastOn,
+		TruncationMarkers:        truncationMarkers,
+		PathTransform:            config.PathTransform,
+		NormalizePathSeparators:  config.NormalizePathSeparators,
+		AllowListSeverityCeiling: config.AllowListSeverityCeiling,
+		DecryptHook:              config.DecryptHook,
+		Fingerprints:             config.Fingerprints,
+		IgnoreFingerprints:       config.IgnoreFingerprints,
+		AllowFunc:                config.AllowFunc,
+		ReportPriority:           config.ReportPriority,
+		PriorityFunc:             config.PriorityFunc,
+		ScanCompressed:           config.ScanCompressed,
+		MatchRegion:              config.MatchRegion,
+		ScanGitConfig:            config.ScanGitConfig,
+		AttributeAuthor:          config.AttributeAuthor,
+		ReportEnclosingFunction:  config.ReportEnclosingFunction,
+		BinaryThreshold:          config.BinaryThreshold,
+		SecretKeyPatterns:        secretKeyPatterns,
+		FindingIndex:             config.FindingIndex,
+		DeduplicateFindings:      config.DeduplicateFindings,
+		DeduplicateSpans:         config.DeduplicateSpans,
+		TrimSecrets:              config.TrimSecrets,
+		TrimQuotes:               config.TrimQuotes,
+		ReportExposure:           config.ReportExposure,
+		ReportLanguage:           config.ReportLanguage,
+		ContextLines:             config.ContextLines,
+		ScanTemplates:            config.ScanTemplates,
+		EmitCleanFiles:           config.EmitCleanFiles,
+		ScanExts:                 config.ScanExts,
+		ScanDataURIs:             config.ScanDataURIs,
+		MaxDepth:                 config.MaxDepth,
+		configHash:               configHash(config),
+		truncationMatchers:       newTruncationMatchers(truncationMarkers),
+		allowUsage:               newAllowUsage(customAllowRules(rules, allowRules)),
+		expiredAllowRules:        expiredAllowRules,
+		commentStats:             newCommentStats(config.ReportCommentStats),
+		verification:             newVerification(config),
+		sink:                     newSink(config.ResultSink),
 	}}
 }
 
 type ScanArgs struct {
 	FilePath string
 	Content  []byte
+
+	// Info is used to report the exposure of the file. It is optional.
+	Info os.FileInfo
 }
 
 type Match struct {
 	Rule     Rule
 	Location Location
+
+	// Region is the whole region matched by the regex. It is the same as Location if the rule has no secret group.
+	Region Location
+
+	// Context describes where the secret is encoded, e.g. in a data URI
+	Context string
+
+	// decoded is the secret if it is encoded in the content at Location
+	decoded []byte
+}
+
+// secret returns the raw secret in the content
+func (m Match) secret(content []byte) []byte {
+	if m.decoded != nil {
+		return m.decoded
+	}
+	return content[m.Location.Start:m.Location.End]
 }
 
 func (s *Scanner) Scan(args ScanArgs) types.Secret {
+	return s.ScanContext(context.Background(), args)
+}
+
+// ScanContext scans the file like Scan. Verifiers are called with the context, so they stop when it is done.
+func (s *Scanner) ScanContext(ctx context.Context, args ScanArgs) types.Secret {
+	args.FilePath = s.transformPath(args.FilePath)
+
 	// Global allowed paths
-	if s.AllowPath(args.FilePath) {
+	pathAllowed := s.allowPath(args.FilePath)
+	if pathAllowed && s.AllowListSeverityCeiling == "" {
 		return types.Secret{
 			FilePath: args.FilePath,
 		}
 	}
 
+	// Scan the plaintext of encrypted files
+	if s.DecryptHook != nil {
+		if plaintext, ok := s.DecryptHook(args.FilePath, args.Content); ok {
+			args.Content = plaintext
+		}
+	}
+
+	// Scan the decompressed content. Report files which fail to decompress not to hide potential secrets silently.
+	if s.Compressed(args.FilePath, args.Content) {
+		decompressed, err := gunzip(args.Content)
+		if err != nil {
+			log.Logger.Debugf("Unable to decompress %s: %s", args.FilePath, err)
+			finding := decodeFailure("gzip", err)
+			if s.suppressed(args.FilePath, finding.Match, nil, &finding) {
+				return types.Secret{
+					FilePath: args.FilePath,
+				}
+			}
+			return types.Secret{
+				FilePath: args.FilePath,
+				Findings: []types.SecretFinding{finding},
+			}
+		}
+		// Decompressed binaries are skipped in the same way as uncompressed ones
+		if binary, err := s.IsBinary(bytes.NewReader(decompressed), int64(len(decompressed))); binary || err != nil {
+			log.Logger.Debugf("Skipping binary content decompressed from %s", args.FilePath)
+			return types.Secret{
+				FilePath: args.FilePath,
+			}
+		}
+		args.Content = decompressed
+	}
+
 	var censored []byte
 	var copyCensored sync.Once
-	var matched []Match
-
 	var findings []types.SecretFinding
+	var secrets []string
+	var requireVerification []bool
+
+	// Detect secrets in the normalized content and map the locations back to the original content
+	scanArgs := args
+	var norms []*normalizer
+	if s.NormalizeUnicode {
+		norms = append(norms, newNormalizer(scanArgs.Content))
+		scanArgs.Content = norms[len(norms)-1].content
+	}
+	if s.CollapseConcatenation {
+		norms = append(norms, collapseConcatenations(scanArgs.Content))
+		scanArgs.Content = norms[len(norms)-1].content
+	}
+	matched := s.findMatches(scanArgs, pathAllowed)
+	for i := len(norms) - 1; i >= 0; i-- {
+		for j := range matched {
+			matched[j].Location = norms[i].original(matched[j].Location)
+			matched[j].Region = norms[i].original(matched[j].Region)
+		}
+	}
+	matched = append(matched, s.findDataURIMatches(args, pathAllowed)...)
+	matched = s.trimMatches(args.Content, matched)
+	matched = mergeMultilineMatches(args.Content, matched)
+	if s.DeduplicateSpans {
+		matched = deduplicateSpans(matched)
+	}
+	if s.DeduplicateFindings {
+		matched = deduplicateMatches(matched)
+	}
+
+	fingerprints := make([]string, len(matched))
+	if s.Fingerprints || len(s.IgnoreFingerprints) > 0 {
+		for i, match := range matched {
+			fingerprints[i] = fingerprint(args.FilePath, match.Rule.ID, match.secret(args.Content))
+		}
+	}
+
+	for _, match := range matched {
+		copyCensored.Do(func() {
+			censored = make([]byte, len(args.Content))
+			copy(censored, args.Content)
+		})
+		censored = censorLocation(match.Location, censored)
+	}
+
+	var heredocs []heredoc
+	if len(matched) > 0 && isShellScript(args.FilePath, args.Content) {
+		heredocs = findHeredocs(args.Content)
+	}
+
+	exposure := s.exposure(args)
+	var lang string
+	if s.ReportLanguage {
+		lang = language(args.FilePath)
+	}
+	template := s.ScanTemplates && isTemplate(args.FilePath)
+
+	var lines []string
+	if len(matched) > 0 && s.ContextLines > 0 {
+		lines = splitLines(censored)
+	}
+
+	var declarations []declaration
+	if len(matched) > 0 && s.ReportEnclosingFunction {
+		declarations = findDeclarations(args.Content)
+	}
+
+	for i, match := range matched {
+		// Ignore known secrets. They are still censored above not to be exposed in the code of other findings.
+		if _, ok := s.IgnoreFingerprints[fingerprints[i]]; ok {
+			continue
+		}
+
+		// Templates usually hold placeholders rather than real secrets
+		if template && placeholderRegex.Match(match.secret(args.Content)) {
+			continue
+		}
+
+		finding := toFinding(match.Rule, match.Location, censored)
+		if template {
+			finding.Severity = lowerSeverity(finding.Severity, 1)
+		}
+		if s.MatchRegion {
+			finding.Match = string(censored[match.Region.Start:match.Region.End])
+			finding.Secret = string(censored[match.Location.Start:match.Location.End])
+		}
+		if s.Fingerprints {
+			finding.Fingerprint = fingerprints[i]
+		}
+		var inComment bool
+		if s.CommentSeverityDelta > 0 || s.commentStats != nil {
+			inComment = s.inComment(args.FilePath, args.Content, match.Location)
+		}
+		if s.CommentSeverityDelta > 0 && inComment {
+			finding.Severity = lowerSeverity(finding.Severity, s.CommentSeverityDelta)
+		}
+		finding.Context = heredocLabel(heredocs, match.Location)
+		if match.Context != "" {
+			finding.Context = match.Context
+		} else if finding.Context == "" {
+			finding.Context = enclosingFunction(declarations, match.Location)
+		}
+		finding.Exposure = exposure
+		finding.Language = lang
+		if s.ContextLines > 0 {
+			finding.LinesBefore, finding.LinesAfter = surroundingLines(lines, finding.StartLine, finding.EndLine, s.ContextLines)
+		}
+		if s.AllowFunc != nil && s.AllowFunc(finding) {
+			continue
+		}
+		s.commentStats.record(inComment)
+		findings = append(findings, finding)
+		secrets = append(secrets, string(match.secret(args.Content)))
+		requireVerification = append(requireVerification, match.Rule.RequireVerification)
+	}
+
+	// Verify the raw secrets. The order of findings is kept until verified.
+	s.verification.verify(ctx, findings, secrets)
+	findings = s.verification.require(findings, requireVerification)
+	s.prioritize(findings)
+
+	if len(findings) == 0 {
+		return s.clean(args.FilePath)
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].RuleID != findings[j].RuleID {
+			return findings[i].RuleID < findings[j].RuleID
+		}
+		return findings[i].Match < findings[j].Match
+	})
+	s.index(findings)
+
+	return types.Secret{
+		FilePath: args.FilePath,
+		Findings: findings,
+	}
+}
+
+// suppressed checks if the finding, which is not detected by rules or detectors, is suppressed in the same way as
+// their findings, i.e. by allow rules, ignored fingerprints and AllowFunc. The fingerprint is set if enabled.
+func (s *Scanner) suppressed(filePath, match string, secret []byte, finding *types.SecretFinding) bool {
+	if (s.allowPath(filePath) || s.used(s.pathRule(finding.RuleID, filePath))) && !s.aboveCeiling(finding.Severity) {
+		return true
+	}
+	if s.used(s.AllowRules.regexRule(finding.RuleID, match)) {
+		return true
+	}
+
+	fp := fingerprint(filePath, finding.RuleID, secret)
+	if _, ok := s.IgnoreFingerprints[fp]; ok {
+		return true
+	}
+	if s.Fingerprints {
+		finding.Fingerprint = fp
+	}
+	return s.AllowFunc != nil && s.AllowFunc(*finding)
+}
+
+// findMatches returns secrets detected by rules and detectors.
+// If pathAllowed is true, only secrets above the allow list severity ceiling are returned.
+func (s *Scanner) findMatches(args ScanArgs, pathAllowed bool) []Match {
+	var matched []Match
 	globalExcludedBlocks := newBlocks(args.Content, s.ExcludeBlock.Regexes)
 	for _, rule := range s.Rules {
 		// Check if the file path should be scanned by this rule
@@ -359,7 +1083,8 @@ func (s *Scanner) Scan(args ScanArgs) types.Secret {
 		}
 
 		// Check if the file path should be allowed
-		if rule.AllowPath(args.FilePath) {
+		if (pathAllowed || s.used(rule.AllowRules.pathRule(rule.ID, args.FilePath)) ||
+			s.used(s.pathRule(rule.ID, args.FilePath))) && !s.aboveCeiling(rule.Severity) {
 			continue
 		}
 
@@ -369,60 +1094,89 @@ func (s *Scanner) Scan(args ScanArgs) types.Secret {
 		}
 
 		// Detect secrets
-		locs := s.FindLocations(rule, args.Content)
-		if len(locs) == 0 {
+		ruleMatches := s.findRuleMatches(rule, args.Content)
+		if len(ruleMatches) == 0 {
 			continue
 		}
 
+		// The cooldown requires the matches in order, but same-named secret groups may capture them out of order
+		sort.SliceStable(ruleMatches, func(i, j int) bool {
+			return ruleMatches[i].Location.Start < ruleMatches[j].Location.Start
+		})
+
 		localExcludedBlocks := newBlocks(args.Content, rule.ExcludeBlock.Regexes)
+		cooldown := newCooldown(args.Content, rule.SuppressWithinLines)
 
-		for _, loc := range locs {
+		for _, match := range ruleMatches {
+			loc := match.Location
 			// Skip the secret if it is within excluded blocks.
 			if globalExcludedBlocks.Match(loc) || localExcludedBlocks.Match(loc) {
 				continue
 			}
 
-			matched = append(matched, Match{
-				Rule:     rule,
-				Location: loc,
-			})
-			copyCensored.Do(func() {
-				censored = make([]byte, len(args.Content))
-				copy(censored, args.Content)
-			})
-			censored = censorLocation(loc, censored)
-		}
-	}
+			// Skip the secret if it is before the delimiter, e.g. in the key
+			if !rule.MatchAfterDelimiter(args.Content, loc) {
+				continue
+			}
 
-	for _, match := range matched {
-		findings = append(findings, toFinding(match.Rule, match.Location, censored))
-	}
+			// Skip the secret if it doesn't look random enough
+			if rule.Entropy > 0 && shannonEntropy(args.Content[loc.Start:loc.End]) < rule.Entropy {
+				continue
+			}
 
-	if len(findings) == 0 {
-		return types.Secret{}
+			// Skip the secret if it is not structurally valid, e.g. a truncated JWT
+			if rule.ValidateAs != "" && !validAs(rule.ValidateAs, args.Content[loc.Start:loc.End]) {
+				continue
+			}
+
+			// Skip the secret if it has just been reported
+			if cooldown.suppress(loc) {
+				continue
+			}
+
+			matched = append(matched, match)
+		}
 	}
 
-	sort.Slice(findings, func(i, j int) bool {
-		if findings[i].RuleID != findings[j].RuleID {
-			return findings[i].RuleID < findings[j].RuleID
+	// Run custom detectors
+	for _, detector := range s.Detectors {
+		fallback := fallbackDetector(detector)
+		for _, match := range s.detect(detector, args) {
+			if globalExcludedBlocks.Match(match.Location) {
+				continue
+			}
+			// Fallback detectors report secrets only if no rule detects them
+			if fallback && overlaps(matched, match.Location) {
+				continue
+			}
+			if (pathAllowed || s.used(s.pathRule(match.Rule.ID, args.FilePath))) && !s.aboveCeiling(match.Rule.Severity) {
+				continue
+			}
+			matched = append(matched, match)
 		}
-		return findings[i].Match < findings[j].Match
-	})
+	}
+	return matched
+}
 
-	return types.Secret{
-		FilePath: args.FilePath,
-		Findings: findings,
+// index numbers the findings sequentially if FindingIndex is enabled
+func (g Global) index(findings []types.SecretFinding) {
+	if !g.FindingIndex {
+		return
+	}
+	for i := range findings {
+		findings[i].Index = i + 1
 	}
 }
 
+// censorLocation censors the secret at the location. Line breaks are kept so that findings after multiline secrets,
+// e.g. private keys, have the right line numbers.
 func censorLocation(loc Location, input []byte) []byte {
-	return append(
-		input[:loc.Start],
-		append(
-			bytes.Repeat([]byte("*"), loc.End-loc.Start),
-			input[loc.End:]...,
-		)...,
-	)
+	for i := loc.Start; i < loc.End; i++ {
+		if input[i] != '\n' {
+			input[i] = '*'
+		}
+	}
+	return input
 }
 
 func toFinding(rule Rule, loc Location, content []byte) types.SecretFinding {
@@ -0,0 +1,323 @@
+package secret
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+var (
+	ErrFailFast            = xerrors.New("secret matching fail-fast-on found")
+	ErrByteBudgetExhausted = xerrors.New("byte budget of the secret scan session exhausted")
+)
+
+// FailFastRule matches secrets of the category with the severity or higher.
+// Empty fields match any category or severity.
+type FailFastRule struct {
+	Category types.SecretRuleCategory `yaml:"category"`
+	Severity string                   `yaml:"severity"`
+}
+
+func (r FailFastRule) match(finding types.SecretFinding) bool {
+	if r.Category != "" && r.Category != finding.Category {
+		return false
+	}
+	return r.Severity == "" || severityIndex(finding.Severity) >= severityIndex(r.Severity)
+}
+
+// Session scans multiple files with the same scanner.
+// It stops scanning once a secret matching FailFastOn is found.
+type Session struct {
+	// ByteBudget limits the total size of the content scanned in the session. Once the budget is consumed,
+	// the remaining files are skipped as SkipReasonByteBudget. Zero means no limit. It must be set before scanning.
+	ByteBudget int64
+
+	scanner Scanner
+	stream  chan<- StreamedFinding
+
+	mu       sync.Mutex
+	err      error
+	coverage Coverage
+	consumed int64
+}
+
+// Coverage reports how many files were considered in a session and why they were not scanned.
+// Considered is always the sum of Scanned and Skipped.
+// A directory which is not walked by ScanDir is counted once, as are unreadable paths.
+type Coverage struct {
+	Considered int
+	Scanned    int
+	Skipped    map[SkipReason]int
+}
+
+// NewSession returns a new scan session
+func (s *Scanner) NewSession() *Session {
+	return &Session{
+		scanner:  *s,
+		coverage: Coverage{Skipped: make(map[SkipReason]int)},
+	}
+}
+
+// StreamedFinding is a finding sent by a streaming session as soon as it is found
+type StreamedFinding struct {
+	FilePath string
+	types.SecretFinding
+}
+
+// NewStreamingSession returns a session which also sends findings to the channel in discovery order,
+// i.e. in the order the files are scanned and by line in each file, e.g. for live-tailing scans.
+// Sending blocks until the finding is received. The channel is not closed by the session.
+func (s *Scanner) NewStreamingSession(ch chan<- StreamedFinding) *Session {
+	session := s.NewSession()
+	session.stream = ch
+	return session
+}
+
+// ScanDir scans the files under the root directory. File paths in results are relative to the root.
+func (s *Scanner) ScanDir(root string) ([]types.Secret, error) {
+	return s.NewSession().ScanDir(root)
+}
+
+// Scan scans the file unless the session has already been stopped.
+// It returns ErrFailFast when the file contains a secret matching FailFastOn
+// and for all the files scanned after that.
+// It returns ErrByteBudgetExhausted without scanning the file once ByteBudget is consumed.
+func (s *Session) Scan(args ScanArgs) (types.Secret, error) {
+	return s.ScanContext(context.Background(), args)
+}
+
+// ScanContext scans the file like Scan. Verifiers are called with the context, so they stop when it is done.
+func (s *Session) ScanContext(ctx context.Context, args ScanArgs) (types.Secret, error) {
+	if err := s.stopped(); err != nil {
+		s.record(SkipReasonFailFast)
+		return types.Secret{}, err
+	}
+	if !s.consume(len(args.Content)) {
+		s.record(SkipReasonByteBudget)
+		return types.Secret{}, ErrByteBudgetExhausted
+	}
+
+	s.record(lo.Ternary(s.scanner.AllowPath(args.FilePath), SkipReasonAllowedPath, ""))
+	secret := s.scanner.ScanContext(ctx, args)
+	s.emit(secret)
+	for _, finding := range secret.Findings {
+		if !s.scanner.failFast(finding) {
+			continue
+		}
+		s.mu.Lock()
+		if s.err == nil {
+			s.err = xerrors.Errorf("%s (%s) in %s: %w", finding.RuleID, finding.Severity, args.FilePath, ErrFailFast)
+		}
+		err := s.err
+		s.mu.Unlock()
+		return secret, err
+	}
+	return secret, nil
+}
+
+// ScanDir scans the files under the root directory which are required by Scanner.Required.
+// File paths in results are relative to the root.
+func (s *Session) ScanDir(root string) ([]types.Secret, error) {
+	var secrets []types.Secret
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil && path == root {
+			return err
+		} else if err != nil {
+			// Keep walking so that a single unreadable path doesn't hide the other files
+			log.Logger.Debugf("Unable to walk %s: %s", path, err)
+			s.record(SkipReasonUnreadable)
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return xerrors.Errorf("relative path error: %w", err)
+		}
+		rel = filepath.ToSlash(rel)
+
+		// Do not walk directories whose files would all be skipped
+		if d.IsDir() && path != root {
+			if reason := s.scanner.skipDirReason(rel); reason != "" {
+				s.record(reason)
+				return filepath.SkipDir
+			}
+			return nil
+		} else if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			log.Logger.Debugf("Unable to get file info of %s: %s", rel, err)
+			s.record(SkipReasonUnreadable)
+			return nil
+		}
+
+		result, err := s.scanFile(context.Background(), rel, info, func() ([]byte, error) {
+			return os.ReadFile(path)
+		})
+		if err != nil {
+			log.Logger.Debugf("Unable to scan %s: %s", rel, err)
+			s.record(SkipReasonUnreadable)
+			return nil
+		}
+		if len(result.Findings) > 0 || result.Clean {
+			secrets = append(secrets, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("walk error: %w", err)
+	}
+	return secrets, s.result()
+}
+
+// scanFile scans the file if it is required and not binary. The content is read only when the file is scanned.
+// It doesn't return the fail-fast error so that the remaining files are accounted for in the coverage.
+func (s *Session) scanFile(ctx context.Context, filePath string, info os.FileInfo, read func() ([]byte, error)) (types.Secret, error) {
+	content, ok, err := s.readFile(filePath, info, read)
+	if err != nil || !ok {
+		return types.Secret{}, err
+	}
+
+	result, _ := s.ScanContext(ctx, ScanArgs{
+		FilePath: filePath,
+		Content:  content,
+		Info:     info,
+	})
+	return result, nil
+}
+
+// readFile reads the file if it is required and not binary, otherwise it records the skip reason and returns false
+func (s *Session) readFile(filePath string, info os.FileInfo, read func() ([]byte, error)) ([]byte, bool, error) {
+	if err := s.stopped(); err != nil {
+		s.record(SkipReasonFailFast)
+		return nil, false, nil
+	}
+	if reason := s.scanner.skipReason(filePath, info); reason != "" {
+		s.record(reason)
+		return nil, false, nil
+	}
+	if info.Size() > maxFileSize {
+		s.record(SkipReasonLargeFile)
+		return nil, false, nil
+	}
+	// Do not read files which can't be scanned
+	if s.exhausted() {
+		s.record(SkipReasonByteBudget)
+		return nil, false, nil
+	}
+
+	content, err := read()
+	if err != nil {
+		return nil, false, xerrors.Errorf("file read error %s: %w", filePath, err)
+	}
+
+	// Do not scan binaries, including compressed files which decompress to binaries
+	binary, err := s.scanner.IsBinary(bytes.NewReader(content), int64(len(content)))
+	if compressed := s.scanner.Compressed(filePath, content); (binary || err != nil) && !compressed ||
+		compressed && s.scanner.decompressedBinary(content) {
+		s.record(SkipReasonBinary)
+		return nil, false, nil
+	}
+	return content, true, nil
+}
+
+// Coverage returns the coverage of the files scanned so far
+func (s *Session) Coverage() Coverage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Coverage{
+		Considered: s.coverage.Considered,
+		Scanned:    s.coverage.Scanned,
+		Skipped:    lo.Assign(s.coverage.Skipped),
+	}
+}
+
+// emit sends the findings to the stream and writes them to the result sink by line.
+// The order of the findings in the result is kept.
+func (s *Session) emit(secret types.Secret) {
+	if s.stream == nil && s.scanner.sink == nil {
+		return
+	}
+	findings := slices.Clone(secret.Findings)
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].StartLine < findings[j].StartLine
+	})
+	for _, finding := range findings {
+		f := StreamedFinding{
+			FilePath:      secret.FilePath,
+			SecretFinding: finding,
+		}
+		s.scanner.sink.write(f)
+		if s.stream != nil {
+			s.stream <- f
+		}
+	}
+}
+
+func (s *Session) stopped() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// result returns the error to be returned after scanning multiple files, i.e. the fail-fast error if stopped,
+// otherwise ErrByteBudgetExhausted if any file is skipped due to the byte budget.
+func (s *Session) result() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil && s.coverage.Skipped[SkipReasonByteBudget] > 0 {
+		return ErrByteBudgetExhausted
+	}
+	return s.err
+}
+
+// consume adds the size to the consumed bytes unless the byte budget has already been consumed
+func (s *Session) consume(size int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ByteBudget > 0 && s.consumed >= s.ByteBudget {
+		return false
+	}
+	s.consumed += int64(size)
+	return true
+}
+
+func (s *Session) exhausted() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ByteBudget > 0 && s.consumed >= s.ByteBudget
+}
+
+// record adds the file to the coverage. An empty reason means the file is scanned.
+func (s *Session) record(reason SkipReason) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.coverage.Considered++
+	if reason == "" {
+		s.coverage.Scanned++
+		return
+	}
+	s.coverage.Skipped[reason]++
+}
+
+func (g Global) failFast(finding types.SecretFinding) bool {
+	for _, rule := range g.FailFastOn {
+		if rule.match(finding) {
+			return true
+		}
+	}
+	return false
+}
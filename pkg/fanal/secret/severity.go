@@ -0,0 +1,26 @@
+package secret
+
+import (
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+)
+
+// severities are ordered from the lowest to the highest
+var severities = []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// severityIndex returns the position of the severity in severities.
+// Unknown values are treated as "UNKNOWN".
+func severityIndex(severity string) int {
+	i := slices.Index(severities, severity)
+	return lo.Ternary(i < 0, 0, i)
+}
+
+// lowerSeverity lowers the severity by delta levels. The result is never lower than "LOW".
+// "UNKNOWN" severity is returned as is.
+func lowerSeverity(severity string, delta int) string {
+	i := severityIndex(severity)
+	if i == 0 || delta <= 0 {
+		return severity
+	}
+	return severities[lo.Ternary(i-delta < 1, 1, i-delta)]
+}
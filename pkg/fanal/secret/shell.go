@@ -0,0 +1,92 @@
+package secret
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	shellExts    = []string{".sh", ".bash", ".zsh", ".ksh"}
+	shebangRegex = regexp.MustCompile(`^#!\s*\S*\b(ba|z|k|da)?sh\b`)
+
+	// e.g. <<EOF, <<-EOF, <<'EOF' and <<"EOF". Here-strings (<<<) are not heredocs.
+	heredocRegex = regexp.MustCompile(`(?:^|[^<])<<(-?)\s*(?:'(\w+)'|"(\w+)"|\\?(\w+))`)
+)
+
+type heredoc struct {
+	label string
+	body  Location
+}
+
+// isShellScript checks the file extension and the shebang
+func isShellScript(path string, content []byte) bool {
+	return slices.Contains(shellExts, filepath.Ext(path)) || shebangRegex.Match(content)
+}
+
+// findHeredocs returns the bodies of heredocs in the shell script
+func findHeredocs(content []byte) []heredoc {
+	var heredocs []heredoc
+	for offset := 0; offset < len(content); {
+		m := heredocRegex.FindSubmatchIndex(content[offset:])
+		if m == nil {
+			break
+		}
+
+		var delimiter string
+		for i := 2; i <= 4; i++ {
+			if m[2*i] != -1 {
+				delimiter = string(content[offset+m[2*i] : offset+m[2*i+1]])
+			}
+		}
+		stripTabs := m[3] > m[2]
+
+		// The body starts at the next line
+		lineEnd := bytes.Index(content[offset+m[1]:], lineSep)
+		if lineEnd == -1 {
+			break
+		}
+		bodyStart := offset + m[1] + lineEnd + 1
+		bodyEnd, next := findHeredocEnd(content, bodyStart, delimiter, stripTabs)
+
+		heredocs = append(heredocs, heredoc{
+			label: fmt.Sprintf("heredoc <<%s", delimiter),
+			body:  Location{Start: bodyStart, End: bodyEnd},
+		})
+		offset = next
+	}
+	return heredocs
+}
+
+// findHeredocEnd returns the end of the heredoc body and the offset after the delimiter line.
+// If the delimiter is not found, the heredoc continues to the end of the content.
+func findHeredocEnd(content []byte, start int, delimiter string, stripTabs bool) (int, int) {
+	for lineStart := start; lineStart < len(content); {
+		lineEnd := bytes.Index(content[lineStart:], lineSep)
+		lineEnd = lo.Ternary(lineEnd == -1, len(content), lineStart+lineEnd)
+
+		line := bytes.TrimSuffix(content[lineStart:lineEnd], []byte("\r"))
+		if stripTabs {
+			line = bytes.TrimLeft(line, "\t")
+		}
+		if string(line) == delimiter {
+			return lineStart, lineEnd
+		}
+		lineStart = lineEnd + 1
+	}
+	return len(content), len(content)
+}
+
+// heredocLabel returns the label of the heredoc containing the location
+func heredocLabel(heredocs []heredoc, loc Location) string {
+	for _, h := range heredocs {
+		if h.body.Match(loc) {
+			return h.label
+		}
+	}
+	return ""
+}
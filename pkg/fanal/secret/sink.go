@@ -0,0 +1,40 @@
+package secret
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/log"
+)
+
+// sink writes findings to ResultSink as JSON lines.
+// Writes are serialized as files may be scanned concurrently, and it is shared by copies of the scanner.
+type sink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newSink(w io.Writer) *sink {
+	if w == nil {
+		return nil
+	}
+	return &sink{w: w}
+}
+
+func (s *sink) write(finding StreamedFinding) {
+	if s == nil {
+		return
+	}
+	b, err := json.Marshal(finding)
+	if err != nil {
+		log.Logger.Warnf("Unable to marshal the finding in %s: %s", finding.FilePath, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err = s.w.Write(append(b, '\n')); err != nil {
+		log.Logger.Warnf("Unable to write the finding in %s to the result sink: %s", finding.FilePath, err)
+	}
+}
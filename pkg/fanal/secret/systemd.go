@@ -0,0 +1,69 @@
+package secret
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+var (
+	systemdUnitExts = []string{".service", ".socket"}
+
+	// e.g. [Service]
+	systemdSectionRegex = regexp.MustCompile(`^\[(?P<section>[^\]]+)\]`)
+
+	// e.g. Environment=API_TOKEN=secret "DB_PASSWORD=secret with spaces"
+	systemdEnvironmentRegex = regexp.MustCompile(`^[ \t]*Environment[ \t]*=[ \t]*`)
+
+	// Assignments are separated by whitespace unless quoted
+	systemdAssignmentRegex = regexp.MustCompile(`"[^"]*"|'[^']*'|[^\s"']+`)
+)
+
+// isSystemdUnit checks if the file is a systemd unit file or a drop-in of it, e.g. app.service.d/override.conf
+func isSystemdUnit(path string) bool {
+	if slices.Contains(systemdUnitExts, filepath.Ext(path)) {
+		return true
+	}
+	dir := filepath.Base(filepath.Dir(path))
+	return filepath.Ext(path) == ".conf" && slices.Contains(systemdUnitExts, filepath.Ext(strings.TrimSuffix(dir, ".d")))
+}
+
+// detectSystemd inspects the variables assigned by Environment= directives in systemd unit files.
+// EnvironmentFile= only refers to other files, so it is not inspected.
+// The section and the variable are reported as the context, e.g. "Service.Environment.API_TOKEN".
+func (d secretKeyDetector) detectSystemd(content []byte) []Finding {
+	var findings []Finding
+	var section string
+	offset := 0
+	for _, line := range bytes.SplitAfter(content, lineSep) {
+		lineStart := offset
+		offset += len(line)
+
+		if m := systemdSectionRegex.FindSubmatch(line); m != nil {
+			section = string(m[1])
+			continue
+		}
+		loc := systemdEnvironmentRegex.FindIndex(line)
+		if loc == nil {
+			continue
+		}
+		for _, a := range systemdAssignmentRegex.FindAllIndex(line[loc[1]:], -1) {
+			start, end := lineStart+loc[1]+a[0], lineStart+loc[1]+a[1]
+			if q := content[start]; q == '"' || q == '\'' {
+				start, end = start+1, end-1
+			}
+			key, value, ok := strings.Cut(string(content[start:end]), "=")
+			// Skip values with escapes as they are not written as is
+			if !ok || !secretKey(d.patterns, key) || !sensitiveValue(key, value) || strings.Contains(value, `\`) {
+				continue
+			}
+			finding := sensitiveKeyFinding(start+len(key)+1, end)
+			finding.Context = strings.Join([]string{section, "Environment", key}, ".")
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
@@ -0,0 +1,54 @@
+package secret
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// ScanTar scans the regular files in the tar stream. File paths in results are the entry names.
+func (s *Scanner) ScanTar(ctx context.Context, r io.Reader) ([]types.Secret, error) {
+	return s.NewSession().ScanTar(ctx, r)
+}
+
+// ScanTar scans the regular files in the tar stream which are required by Scanner.Required.
+// The headers are used for the filtering so that skipped entries are not read into memory.
+func (s *Session) ScanTar(ctx context.Context, r io.Reader) ([]types.Secret, error) {
+	var secrets []types.Secret
+	tr := tar.NewReader(r)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, xerrors.Errorf("tar scan canceled: %w", err)
+		}
+
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, xerrors.Errorf("tar read error: %w", err)
+		}
+		if !hdr.FileInfo().Mode().IsRegular() {
+			continue
+		}
+
+		// e.g. "./app/config.env" => "app/config.env"
+		filePath := strings.TrimPrefix(path.Clean("/"+hdr.Name), "/")
+		result, err := s.scanFile(ctx, filePath, hdr.FileInfo(), func() ([]byte, error) {
+			return io.ReadAll(tr)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(result.Findings) > 0 || result.Clean {
+			secrets = append(secrets, result)
+		}
+	}
+	return secrets, s.result()
+}
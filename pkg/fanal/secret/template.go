@@ -0,0 +1,38 @@
+package secret
+
+import (
+	"path/filepath"
+	"regexp"
+
+	"golang.org/x/exp/slices"
+)
+
+const examplesAllowRuleID = "examples"
+
+var (
+	templateExts = []string{".example", ".template", ".sample"}
+
+	// e.g. your_api_key_here, <api-key>, ${API_KEY}, changeme and ghp_xxxxxxxx
+	placeholderRegex = regexp.MustCompile(`(?i)^(your|my|example|sample|dummy|fake|placeholder|change_?me|replace|todo|<|\$\{|\{\{|\*{3})` +
+		`|(here|change_?me|example|placeholder|x{4,}|\*{3,}|>|\}\})$`)
+)
+
+// isTemplate checks if the file is a template such as .env.example
+func isTemplate(path string) bool {
+	return slices.Contains(templateExts, filepath.Ext(path))
+}
+
+// pathRule returns the first global allow rule applying to the rule ID and matching the path.
+// With ScanTemplates, template files are not allowed as examples since real secrets occasionally slip in.
+func (g Global) pathRule(ruleID, path string) *AllowRule {
+	template := g.ScanTemplates && isTemplate(path)
+	for i, rule := range g.AllowRules {
+		if template && rule.ID == examplesAllowRuleID {
+			continue
+		}
+		if rule.appliesTo(ruleID) && rule.MatchPath(path) {
+			return &g.AllowRules[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,3 @@
+package main
+
+const token = "ghp_" + "ABCDEFGHIJ0123456789" + "abcdefghij012345"
@@ -0,0 +1,17 @@
+provider "aws" {
+  region     = "us-east-1"
+  secret_key = "bQ3x7Kp9Lw2Zr5Tn8Vy1"
+}
+
+variable "db_password" {
+  type    = string
+  default = "Zx9!kq2LmP0rT"
+}
+
+variable "db_user" {
+  default = "administrator"
+}
+
+resource "aws_db_instance" "db" {
+  password = var.db_password
+}
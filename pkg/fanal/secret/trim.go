@@ -0,0 +1,43 @@
+package secret
+
+import "bytes"
+
+const (
+	trimWhitespace = " \t\r\n\f\v"
+	trimQuotes     = "\"'`"
+)
+
+// trimMatches trims whitespace and/or quotes captured at both ends of the secrets as configured,
+// so that fingerprints and censoring cover only the secrets themselves. Regions are not changed.
+// Matches consisting only of the trimmed characters are kept as is not to hide them.
+func (s *Scanner) trimMatches(content []byte, matches []Match) []Match {
+	var cutset string
+	if s.TrimSecrets {
+		cutset += trimWhitespace
+	}
+	if s.TrimQuotes {
+		cutset += trimQuotes
+	}
+	if cutset == "" {
+		return matches
+	}
+
+	for i, m := range matches {
+		// Secrets decoded from data URIs are not located in the content
+		if m.decoded != nil {
+			continue
+		}
+		secret := content[m.Location.Start:m.Location.End]
+		trimmed := bytes.TrimLeft(secret, cutset)
+		start := m.Location.Start + len(secret) - len(trimmed)
+		trimmed = bytes.TrimRight(trimmed, cutset)
+		if len(trimmed) == 0 {
+			continue
+		}
+		matches[i].Location = Location{
+			Start: start,
+			End:   start + len(trimmed),
+		}
+	}
+	return matches
+}
@@ -0,0 +1,61 @@
+package secret
+
+import (
+	"bytes"
+	"unicode"
+)
+
+// defaultTruncationMarkers are used when Config.TruncationMarkers is not specified.
+// Logs and documents often truncate or redact secrets, e.g. "abc123..." and "[REDACTED]".
+var defaultTruncationMarkers = []string{"...", "****", "REDACTED", "<redacted>"}
+
+// defaultTruncationMatchers are compiled from defaultTruncationMarkers
+var defaultTruncationMatchers = newTruncationMatchers(defaultTruncationMarkers)
+
+// truncationMatcher is a truncation marker prepared once so that it is not converted for every secret
+type truncationMatcher struct {
+	marker []byte // in lower case
+	letter bool   // whether the marker contains letters
+}
+
+func newTruncationMatchers(markers []string) []truncationMatcher {
+	var matchers []truncationMatcher
+	for _, marker := range markers {
+		if marker == "" {
+			continue
+		}
+		m := bytes.ToLower([]byte(marker))
+		matchers = append(matchers, truncationMatcher{
+			marker: m,
+			letter: bytes.IndexFunc(m, unicode.IsLetter) >= 0,
+		})
+	}
+	return matchers
+}
+
+// truncated checks if the secret ends with a truncation marker or is immediately followed by one.
+// Markers containing letters, such as "REDACTED", match anywhere in the secret.
+// Other markers don't as characters like "." may be a part of valid secrets, e.g. password hashes.
+func (g Global) truncated(content []byte, loc Location) bool {
+	secret := content[loc.Start:loc.End]
+	var lowerSecret []byte
+	for _, m := range g.truncationMatchers {
+		n := len(m.marker)
+		if len(secret) >= n && bytes.EqualFold(secret[len(secret)-n:], m.marker) {
+			return true
+		}
+		// Only the bytes right after the secret are compared not to copy the rest of the content
+		if loc.End+n <= len(content) && bytes.EqualFold(content[loc.End:loc.End+n], m.marker) {
+			return true
+		}
+		if m.letter {
+			if lowerSecret == nil {
+				lowerSecret = bytes.ToLower(secret)
+			}
+			if bytes.Contains(lowerSecret, m.marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+)
+
+// Types of values which rules can require secrets to be with ValidateAs
+const (
+	ValidateAsJWT    = "jwt"
+	ValidateAsBase64 = "base64"
+	ValidateAsHex    = "hex"
+	ValidateAsUUID   = "uuid"
+)
+
+var uuidRegex = regexp.MustCompile(`^[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}$`)
+
+var validators = map[string]func([]byte) bool{
+	ValidateAsJWT:    validJWT,
+	ValidateAsBase64: validBase64,
+	ValidateAsHex:    validHex,
+	ValidateAsUUID:   uuidRegex.Match,
+}
+
+// validAs checks if the secret is a syntactically valid value of the type. Unknown types are not validated.
+func validAs(kind string, secret []byte) bool {
+	validator, ok := validators[kind]
+	return !ok || validator(secret)
+}
+
+// validJWT checks if the secret consists of a header, a payload and a signature encoded in base64url,
+// and the header and the payload are JSON objects. The signature may be empty for unsecured JWTs.
+func validJWT(secret []byte) bool {
+	parts := bytes.Split(secret, []byte("."))
+	if len(parts) != 3 {
+		return false
+	}
+	for i, part := range parts {
+		decoded, err := base64.RawURLEncoding.DecodeString(string(bytes.TrimRight(part, "=")))
+		if err != nil {
+			return false
+		}
+		if i < 2 && (!json.Valid(decoded) || !bytes.HasPrefix(bytes.TrimSpace(decoded), []byte("{"))) {
+			return false
+		}
+	}
+	return true
+}
+
+// validBase64 checks if the secret is encoded in the standard or URL-safe base64, with or without padding
+func validBase64(secret []byte) bool {
+	if len(secret) == 0 {
+		return false
+	}
+	s := string(bytes.TrimRight(secret, "="))
+	for _, encoding := range []*base64.Encoding{base64.RawStdEncoding, base64.RawURLEncoding} {
+		if _, err := encoding.DecodeString(s); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func validHex(secret []byte) bool {
+	if len(secret) == 0 {
+		return false
+	}
+	_, err := hex.DecodeString(string(secret))
+	return err == nil
+}
@@ -0,0 +1,163 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+	"golang.org/x/time/rate"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/log"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+const (
+	VerificationLive    types.SecretVerificationStatus = "live"
+	VerificationRevoked types.SecretVerificationStatus = "revoked"
+	VerificationUnknown types.SecretVerificationStatus = "unknown"
+
+	// ConfidenceLow is the confidence of secrets which require verification but can't be verified
+	ConfidenceLow = "LOW"
+
+	defaultVerifyConcurrency = 4
+	defaultVerifyBackoff     = time.Second
+	maxVerifyRetries         = 3
+)
+
+// ErrRateLimited should be returned by Verifier when the provider responds with 429 Too Many Requests.
+// The verification is retried with exponential backoff.
+var ErrRateLimited = xerrors.New("verification rate limited")
+
+// Verifier checks if a secret is live, e.g. by calling the API of the provider.
+type Verifier interface {
+	Verify(ctx context.Context, secret string) (types.SecretVerificationStatus, error)
+}
+
+// verification bounds the rate and the concurrency of verification calls.
+// It is shared by copies of the scanner so that the bounds apply to the whole scan.
+type verification struct {
+	verifiers  map[string]Verifier
+	limiter    *rate.Limiter
+	sem        chan struct{}
+	backoff    time.Duration
+	severities map[types.SecretVerificationStatus]string
+}
+
+func newVerification(config *Config) *verification {
+	if len(config.Verifiers) == 0 {
+		return nil
+	}
+
+	limit := rate.Inf
+	if config.VerifyRateLimit > 0 {
+		limit = rate.Limit(config.VerifyRateLimit)
+	}
+	concurrency := lo.Ternary(config.VerifyConcurrency > 0, config.VerifyConcurrency, defaultVerifyConcurrency)
+	return &verification{
+		verifiers: config.Verifiers,
+		limiter:   rate.NewLimiter(limit, 1),
+		sem:       make(chan struct{}, concurrency),
+		backoff:   lo.Ternary(config.VerifyBackoff > 0, config.VerifyBackoff, defaultVerifyBackoff),
+		severities: lo.PickBy(config.VerificationSeverityMap, func(status types.SecretVerificationStatus, severity string) bool {
+			if !slices.Contains(severities, severity) {
+				log.Logger.Warnf("Invalid severity %q for the verification status %q", severity, status)
+				return false
+			}
+			return true
+		}),
+	}
+}
+
+// verify verifies the secrets of the findings found by rules with a verifier
+// and overrides their severity by the status. secrets[i] is the raw secret of findings[i].
+func (v *verification) verify(ctx context.Context, findings []types.SecretFinding, secrets []string) {
+	if v == nil {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := range findings {
+		verifier, ok := v.verifiers[findings[i].RuleID]
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			findings[i].Verification = v.retry(ctx, verifier, findings[i].RuleID, secrets[i])
+			if severity, ok := v.severities[findings[i].Verification]; ok {
+				findings[i].Severity = severity
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// require drops the findings of rules requiring verification unless they are verified live.
+// The findings are kept with low confidence if the rule has no verifier. required[i] is for findings[i].
+func (v *verification) require(findings []types.SecretFinding, required []bool) []types.SecretFinding {
+	var kept []types.SecretFinding
+	for i, finding := range findings {
+		if required[i] {
+			if !v.enabled(finding.RuleID) {
+				finding.Confidence = ConfidenceLow
+			} else if finding.Verification != VerificationLive {
+				continue
+			}
+		}
+		kept = append(kept, finding)
+	}
+	return kept
+}
+
+func (v *verification) enabled(ruleID string) bool {
+	if v == nil {
+		return false
+	}
+	_, ok := v.verifiers[ruleID]
+	return ok
+}
+
+// retry calls the verifier until it is not rate limited, doubling the backoff each time.
+// The status is unknown if the verification fails.
+func (v *verification) retry(ctx context.Context, verifier Verifier, ruleID, secret string) types.SecretVerificationStatus {
+	backoff := v.backoff
+	for i := 0; ; i++ {
+		status, err := v.call(ctx, verifier, secret)
+		if err == nil {
+			return status
+		} else if !errors.Is(err, ErrRateLimited) || i == maxVerifyRetries {
+			log.Logger.Debugf("Secret verification error (%s): %s", ruleID, err)
+			return VerificationUnknown
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return VerificationUnknown
+		}
+		backoff *= 2
+	}
+}
+
+func (v *verification) call(ctx context.Context, verifier Verifier, secret string) (types.SecretVerificationStatus, error) {
+	// select doesn't prefer ctx.Done() when a slot is free as well
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	select {
+	case v.sem <- struct{}{}:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	defer func() { <-v.sem }()
+
+	if err := v.limiter.Wait(ctx); err != nil {
+		return "", xerrors.Errorf("rate limit error: %w", err)
+	}
+	return verifier.Verify(ctx, secret)
+}
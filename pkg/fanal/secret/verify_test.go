@@ -0,0 +1,276 @@
+package secret_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/secret"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// mockVerifier records the verification calls
+type mockVerifier struct {
+	delay       time.Duration
+	rateLimited int // the number of calls returning ErrRateLimited first
+
+	mu          sync.Mutex
+	calls       []time.Time
+	inFlight    int
+	maxInFlight int
+}
+
+func (v *mockVerifier) Verify(_ context.Context, s string) (types.SecretVerificationStatus, error) {
+	v.mu.Lock()
+	v.calls = append(v.calls, time.Now())
+	v.inFlight++
+	if v.inFlight > v.maxInFlight {
+		v.maxInFlight = v.inFlight
+	}
+	limited := len(v.calls) <= v.rateLimited
+	v.mu.Unlock()
+
+	time.Sleep(v.delay)
+
+	v.mu.Lock()
+	v.inFlight--
+	v.mu.Unlock()
+
+	if limited {
+		return "", secret.ErrRateLimited
+	}
+	if strings.Contains(s, "revoked") {
+		return secret.VerificationRevoked, nil
+	}
+	return secret.VerificationLive, nil
+}
+
+func TestScanner_Verify(t *testing.T) {
+	content, err := os.ReadFile("testdata/verification.txt")
+	require.NoError(t, err)
+
+	scanContext := func(ctx context.Context, config secret.Config) []types.SecretFinding {
+		config.CustomRules = []secret.Rule{
+			{
+				ID:              "token",
+				Category:        "general",
+				Title:           "Token",
+				Severity:        "HIGH",
+				Regex:           secret.MustCompile(`token=(?P<secret>tok_\w+)`),
+				SecretGroupName: "secret",
+			},
+		}
+		s := secret.NewScanner(&config)
+		return s.ScanContext(ctx, secret.ScanArgs{
+			FilePath: "testdata/verification.txt",
+			Content:  content,
+		}).Findings
+	}
+	scan := func(config secret.Config) []types.SecretFinding {
+		return scanContext(context.Background(), config)
+	}
+
+	t.Run("rate limit", func(t *testing.T) {
+		verifier := &mockVerifier{}
+		findings := scan(secret.Config{
+			Verifiers:       map[string]secret.Verifier{"token": verifier},
+			VerifyRateLimit: 20,
+		})
+		require.Len(t, findings, 6)
+
+		statuses := map[int]types.SecretVerificationStatus{}
+		for _, finding := range findings {
+			statuses[finding.StartLine] = finding.Verification
+		}
+		assert.Equal(t, map[int]types.SecretVerificationStatus{
+			1: secret.VerificationLive,
+			2: secret.VerificationRevoked,
+			3: secret.VerificationLive,
+			4: secret.VerificationLive,
+			5: secret.VerificationLive,
+			6: secret.VerificationLive,
+		}, statuses)
+
+		// 20 calls per second allow a call every 50ms
+		require.Len(t, verifier.calls, 6)
+		assert.GreaterOrEqual(t, verifier.calls[5].Sub(verifier.calls[0]), 225*time.Millisecond)
+	})
+
+	t.Run("concurrency", func(t *testing.T) {
+		verifier := &mockVerifier{delay: 20 * time.Millisecond}
+		findings := scan(secret.Config{
+			Verifiers:         map[string]secret.Verifier{"token": verifier},
+			VerifyConcurrency: 2,
+		})
+		require.Len(t, findings, 6)
+		assert.Len(t, verifier.calls, 6)
+		assert.Equal(t, 2, verifier.maxInFlight)
+	})
+
+	t.Run("backoff on rate limited", func(t *testing.T) {
+		verifier := &mockVerifier{rateLimited: 2}
+		findings := scan(secret.Config{
+			Verifiers:         map[string]secret.Verifier{"token": verifier},
+			VerifyConcurrency: 1,
+			VerifyBackoff:     10 * time.Millisecond,
+		})
+		require.Len(t, findings, 6)
+		for _, finding := range findings {
+			assert.NotEqual(t, secret.VerificationUnknown, finding.Verification)
+		}
+
+		assert.Len(t, verifier.calls, 8)
+	})
+
+	t.Run("give up after retries", func(t *testing.T) {
+		verifier := &mockVerifier{rateLimited: 100}
+		start := time.Now()
+		findings := scan(secret.Config{
+			Verifiers:     map[string]secret.Verifier{"token": verifier},
+			VerifyBackoff: 10 * time.Millisecond,
+		})
+		require.Len(t, findings, 6)
+		for _, finding := range findings {
+			assert.Equal(t, secret.VerificationUnknown, finding.Verification)
+		}
+
+		// Each secret is retried 3 times after 10ms, 20ms and 40ms
+		assert.Len(t, verifier.calls, 24)
+		assert.GreaterOrEqual(t, time.Since(start), 70*time.Millisecond)
+	})
+
+	t.Run("severity map", func(t *testing.T) {
+		findings := scan(secret.Config{
+			Verifiers: map[string]secret.Verifier{"token": &mockVerifier{}},
+			VerificationSeverityMap: map[types.SecretVerificationStatus]string{
+				secret.VerificationLive:    "CRITICAL",
+				secret.VerificationRevoked: "LOW",
+				secret.VerificationUnknown: "INFO", // invalid severities are ignored
+			},
+		})
+		require.Len(t, findings, 6)
+
+		severities := map[int]string{}
+		for _, finding := range findings {
+			severities[finding.StartLine] = finding.Severity
+		}
+		assert.Equal(t, map[int]string{
+			1: "CRITICAL",
+			2: "LOW",
+			3: "CRITICAL",
+			4: "CRITICAL",
+			5: "CRITICAL",
+			6: "CRITICAL",
+		}, severities)
+	})
+
+	t.Run("unknown keeps the rule severity", func(t *testing.T) {
+		findings := scan(secret.Config{
+			Verifiers:     map[string]secret.Verifier{"token": &mockVerifier{rateLimited: 100}},
+			VerifyBackoff: time.Millisecond,
+			VerificationSeverityMap: map[types.SecretVerificationStatus]string{
+				secret.VerificationLive: "CRITICAL",
+			},
+		})
+		require.Len(t, findings, 6)
+		for _, finding := range findings {
+			assert.Equal(t, secret.VerificationUnknown, finding.Verification)
+			assert.Equal(t, "HIGH", finding.Severity)
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		verifier := &mockVerifier{}
+		findings := scanContext(ctx, secret.Config{
+			Verifiers: map[string]secret.Verifier{"token": verifier},
+		})
+		require.Len(t, findings, 6)
+		for _, finding := range findings {
+			assert.Equal(t, secret.VerificationUnknown, finding.Verification)
+		}
+		assert.Empty(t, verifier.calls)
+	})
+
+	t.Run("no verifiers", func(t *testing.T) {
+		for _, finding := range scan(secret.Config{}) {
+			assert.Empty(t, finding.Verification)
+		}
+	})
+}
+
+func TestScanner_RequireVerification(t *testing.T) {
+	content, err := os.ReadFile("testdata/verification.txt")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		verifiers map[string]secret.Verifier
+		want      map[int]string // confidence by line
+	}{
+		{
+			name:      "verification enabled",
+			verifiers: map[string]secret.Verifier{"token": &mockVerifier{}},
+			want: map[int]string{
+				1: "",
+				3: "",
+				4: "",
+				5: "",
+				6: "",
+			},
+		},
+		{
+			name:      "verification gives up",
+			verifiers: map[string]secret.Verifier{"token": &mockVerifier{rateLimited: 100}},
+			want:      map[int]string{},
+		},
+		{
+			name: "verification disabled",
+			want: map[int]string{
+				1: secret.ConfidenceLow,
+				2: secret.ConfidenceLow,
+				3: secret.ConfidenceLow,
+				4: secret.ConfidenceLow,
+				5: secret.ConfidenceLow,
+				6: secret.ConfidenceLow,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := secret.NewScanner(&secret.Config{
+				CustomRules: []secret.Rule{
+					{
+						ID:                  "token",
+						Category:            "general",
+						Title:               "Token",
+						Severity:            "HIGH",
+						Regex:               secret.MustCompile(`token=(?P<secret>tok_\w+)`),
+						SecretGroupName:     "secret",
+						RequireVerification: true,
+					},
+				},
+				Verifiers:     tt.verifiers,
+				VerifyBackoff: time.Millisecond,
+			})
+			got := s.Scan(secret.ScanArgs{
+				FilePath: "testdata/verification.txt",
+				Content:  content,
+			})
+
+			confidences := map[int]string{}
+			for _, finding := range got.Findings {
+				confidences[finding.StartLine] = finding.Confidence
+			}
+			assert.Equal(t, tt.want, confidences)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package secret
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// ScanWorkingTreeChanges scans only the lines added or modified in the working tree since HEAD,
+// i.e. files reported by git status, for fast local feedback. Line numbers are of the working tree.
+// Untracked files and all files in repositories without commits are scanned entirely.
+func (s *Scanner) ScanWorkingTreeChanges(repoPath string) ([]types.Secret, error) {
+	return s.NewSession().ScanWorkingTreeChanges(repoPath)
+}
+
+// ScanWorkingTreeChanges scans the lines changed in the files required by Scanner.Required, like ScanDir.
+func (s *Session) ScanWorkingTreeChanges(repoPath string) ([]types.Secret, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, xerrors.Errorf("git open error: %w", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, xerrors.Errorf("git worktree error: %w", err)
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return nil, xerrors.Errorf("git status error: %w", err)
+	}
+
+	var head *object.Commit
+	if ref, err := repo.Head(); err == nil {
+		if head, err = repo.CommitObject(ref.Hash()); err != nil {
+			return nil, xerrors.Errorf("git commit error: %w", err)
+		}
+	} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, xerrors.Errorf("git head error: %w", err)
+	}
+
+	var paths []string
+	for path, fs := range status {
+		if fs.Worktree == git.Deleted || fs.Worktree == git.Unmodified && fs.Staging == git.Unmodified {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var b *blamer
+	if s.scanner.AttributeAuthor {
+		b = newBlamer(repo)
+	}
+
+	var secrets []types.Secret
+	for _, path := range paths {
+		filePath := filepath.Join(repoPath, filepath.FromSlash(path))
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return nil, xerrors.Errorf("file stat error %s: %w", path, err)
+		}
+		content, ok, err := s.readFile(path, info, func() ([]byte, error) {
+			return os.ReadFile(filePath)
+		})
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		result := s.scanGitFile(b, path, content, status[path].Worktree != git.Untracked)
+		if len(result.Findings) == 0 {
+			continue
+		}
+
+		changed, err := changedLines(head, path, string(content))
+		if err != nil {
+			return nil, xerrors.Errorf("git diff error %s: %w", path, err)
+		}
+		result.Findings = filterChangedLines(result.Findings, changed)
+		if len(result.Findings) > 0 {
+			secrets = append(secrets, result)
+		}
+	}
+	return secrets, s.result()
+}
+
+// changedLines returns the 1-based numbers of the lines added or modified in the content since the commit.
+// nil means all the lines are new.
+func changedLines(head *object.Commit, path, content string) (map[int]struct{}, error) {
+	if head == nil {
+		return nil, nil
+	}
+	file, err := head.File(path)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	original, err := file.Contents()
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[int]struct{})
+	line := 1
+	for _, d := range diff.Do(original, content) {
+		n := strings.Count(d.Text, "\n")
+		if !strings.HasSuffix(d.Text, "\n") {
+			n++
+		}
+		switch d.Type {
+		case diffmatchpatch.DiffEqual:
+			line += n
+		case diffmatchpatch.DiffInsert:
+			for i := 0; i < n; i++ {
+				changed[line] = struct{}{}
+				line++
+			}
+		}
+	}
+	return changed, nil
+}
+
+// filterChangedLines keeps the findings on any of the changed lines. All the findings are kept if changed is nil.
+func filterChangedLines(findings []types.SecretFinding, changed map[int]struct{}) []types.SecretFinding {
+	if changed == nil {
+		return findings
+	}
+	var kept []types.SecretFinding
+	for _, finding := range findings {
+		for line := finding.StartLine; line <= finding.EndLine; line++ {
+			if _, ok := changed[line]; ok {
+				kept = append(kept, finding)
+				break
+			}
+		}
+	}
+	return kept
+}
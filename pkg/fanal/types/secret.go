@@ -1,20 +1,43 @@
 package types
 
+import "time"
+
 type SecretRuleCategory string
 
+type SecretVerificationStatus string
+
 type Secret struct {
 	FilePath string
 	Findings []SecretFinding
+
+	// Clean is true if the file was scanned and no secrets were found. Populated with emit-clean-files
+	Clean      bool   `json:",omitempty"`
+	ConfigHash string `json:",omitempty"` // hash of the secret config the file was scanned with
 }
 
 type SecretFinding struct {
-	RuleID    string
-	Category  SecretRuleCategory
-	Severity  string
-	Title     string
-	StartLine int
-	EndLine   int
-	Code      Code
-	Match     string
-	Layer     Layer `json:",omitempty"`
+	RuleID       string
+	Category     SecretRuleCategory
+	Severity     string
+	Title        string
+	StartLine    int
+	EndLine      int
+	Code         Code
+	Match        string
+	Secret       string                   `json:",omitempty"` // censored secret. Populated with match-region
+	Context      string                   `json:",omitempty"` // e.g. the label of the heredoc containing the secret
+	Tracked      *bool                    `json:",omitempty"` // whether the file is tracked by git. nil if unknown
+	Author       string                   `json:",omitempty"` // author of the line by git blame. Populated with attribute-author
+	AuthorEmail  string                   `json:",omitempty"`
+	Fingerprint  string                   `json:",omitempty"` // stable identifier of the secret across scans
+	Verification SecretVerificationStatus `json:",omitempty"` // result of live verification. Empty if not verified
+	Confidence   string                   `json:",omitempty"` // e.g. LOW for secrets of rules requiring verification without a verifier
+	FirstSeen    *time.Time               `json:",omitempty"` // when the secret was first observed. Set by secret.StampFirstSeen
+	Exposure     []string                 `json:",omitempty"` // e.g. "world-readable". Populated with report-exposure
+	Language     string                   `json:",omitempty"` // e.g. "go" and "yaml". Populated with report-language
+	Priority     string                   `json:",omitempty"` // remediation priority from P0 to P3. Populated with report-priority
+	LinesBefore  []string                 `json:",omitempty"` // lines above the finding. Populated with context-lines
+	LinesAfter   []string                 `json:",omitempty"` // lines below the finding. Populated with context-lines
+	Index        int                      `json:",omitempty"` // 1-based position of the finding in the file. Populated with finding-index
+	Layer        Layer                    `json:",omitempty"`
 }
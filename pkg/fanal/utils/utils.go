@@ -69,6 +69,12 @@ func IsExecutable(fileInfo os.FileInfo) bool {
 }
 
 func IsBinary(content dio.ReadSeekerAt, fileSize int64) (bool, error) {
+	return IsBinaryWithThreshold(content, fileSize, 0)
+}
+
+// IsBinaryWithThreshold checks if the fraction of non-text bytes in the head exceeds the threshold.
+// The threshold 0 means that a single non-text byte makes the content binary.
+func IsBinaryWithThreshold(content dio.ReadSeekerAt, fileSize int64, threshold float64) (bool, error) {
 	headSize := int(math.Min(float64(fileSize), 300))
 	head := make([]byte, headSize)
 	if _, err := content.Read(head); err != nil {
@@ -79,11 +85,12 @@ func IsBinary(content dio.ReadSeekerAt, fileSize int64) (bool, error) {
 	}
 
 	// cf. https://github.com/file/file/blob/f2a6e7cb7db9b5fd86100403df6b2f830c7f22ba/src/encoding.c#L151-L228
+	var nonText int
 	for _, b := range head {
 		if b < 7 || b == 11 || (13 < b && b < 27) || (27 < b && b < 0x20) || b == 0x7f {
-			return true, nil
+			nonText++
 		}
 	}
 
-	return false, nil
+	return float64(nonText) > threshold*float64(len(head)), nil
 }
@@ -2,7 +2,9 @@ package utils
 
 import (
 	"bufio"
+	"bytes"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -31,3 +33,29 @@ func TestIsGzip(t *testing.T) {
 		})
 	}
 }
+
+func TestIsBinaryWithThreshold(t *testing.T) {
+	mostlyText := []byte(strings.Repeat("2022-11-01 12:00:00 INFO request served\n", 5) + "\x00")
+	var tests = []struct {
+		name      string
+		content   []byte
+		threshold float64
+		want      bool
+	}{
+		{"text", []byte("password=secret\n"), 0, false},
+		{"mostly text with strict threshold", mostlyText, 0, true},
+		{"mostly text with threshold", mostlyText, 0.1, false},
+		{"binary with threshold", []byte("\x00\x01\x02\x03text"), 0.1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsBinaryWithThreshold(bytes.NewReader(tt.content), int64(len(tt.content)), tt.threshold)
+			if err != nil {
+				t.Fatalf("unknown error: %s", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}